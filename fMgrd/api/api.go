@@ -36,12 +36,13 @@ func InitApiLayer(server *server.FMGRServer) {
 	svr.Logger.Info("Initializing API Layer")
 }
 
-func GetBulkFault(fromIdx, count int) (*objects.FaultStateGetInfo, error) {
+func GetBulkFault(fromIdx, count int, filter *objects.StateFilter) (*objects.FaultStateGetInfo, error) {
 	svr.ReqChan <- &server.ServerRequest{
 		Op: server.GET_BULK_FAULT_STATE,
 		Data: interface{}(&server.GetBulkInArgs{
 			FromIdx: fromIdx,
 			Count:   count,
+			Filter:  filter,
 		}),
 	}
 	ret := <-svr.ReplyChan
@@ -52,12 +53,13 @@ func GetBulkFault(fromIdx, count int) (*objects.FaultStateGetInfo, error) {
 	}
 }
 
-func GetBulkAlarm(fromIdx, count int) (*objects.AlarmStateGetInfo, error) {
+func GetBulkAlarm(fromIdx, count int, filter *objects.StateFilter) (*objects.AlarmStateGetInfo, error) {
 	svr.ReqChan <- &server.ServerRequest{
 		Op: server.GET_BULK_ALARM_STATE,
 		Data: interface{}(&server.GetBulkInArgs{
 			FromIdx: fromIdx,
 			Count:   count,
+			Filter:  filter,
 		}),
 	}
 	ret := <-svr.ReplyChan
@@ -68,6 +70,136 @@ func GetBulkAlarm(fromIdx, count int) (*objects.AlarmStateGetInfo, error) {
 	}
 }
 
+func GetFaultByCursor(cursor *objects.StateCursor, filter *objects.StateFilter) (*objects.FaultStateCursorInfo, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_FAULT_STATE_BY_CURSOR,
+		Data: interface{}(&server.GetByCursorInArgs{
+			Cursor: cursor,
+			Filter: filter,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetFaultStateByCursorOutArgs); ok {
+		return retObj.CursorInfo, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetFaultStateByCursor")
+}
+
+func GetAlarmByCursor(cursor *objects.StateCursor, filter *objects.StateFilter) (*objects.AlarmStateCursorInfo, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_ALARM_STATE_BY_CURSOR,
+		Data: interface{}(&server.GetByCursorInArgs{
+			Cursor: cursor,
+			Filter: filter,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetAlarmStateByCursorOutArgs); ok {
+		return retObj.CursorInfo, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetAlarmStateByCursor")
+}
+
+func GetFault(ownerId, eventId int, ownerName, eventName, srcObjName, srcObjUUID string) (*objects.FaultState, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_FAULT_STATE,
+		Data: interface{}(&server.GetStateInArgs{
+			OwnerId:    ownerId,
+			EventId:    eventId,
+			OwnerName:  ownerName,
+			EventName:  eventName,
+			SrcObjName: srcObjName,
+			SrcObjUUID: srcObjUUID,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetFaultStateOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetFaultState")
+}
+
+func GetAlarm(ownerId, eventId int, ownerName, eventName, srcObjName, srcObjUUID string) (*objects.AlarmState, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_ALARM_STATE,
+		Data: interface{}(&server.GetStateInArgs{
+			OwnerId:    ownerId,
+			EventId:    eventId,
+			OwnerName:  ownerName,
+			EventName:  eventName,
+			SrcObjName: srcObjName,
+			SrcObjUUID: srcObjUUID,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetAlarmStateOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetAlarmState")
+}
+
+func GetBulkActiveFault(fromIdx, count int) (*objects.ActiveFaultGetInfo, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_BULK_ACTIVE_FAULT,
+		Data: interface{}(&server.GetBulkInArgs{
+			FromIdx: fromIdx,
+			Count:   count,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetBulkActiveFaultOutArgs); ok {
+		return retObj.BulkInfo, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetBulkActiveFault")
+}
+
+func GetBulkActiveAlarm(fromIdx, count int) (*objects.ActiveAlarmGetInfo, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_BULK_ACTIVE_ALARM,
+		Data: interface{}(&server.GetBulkInArgs{
+			FromIdx: fromIdx,
+			Count:   count,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetBulkActiveAlarmOutArgs); ok {
+		return retObj.BulkInfo, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetBulkActiveAlarm")
+}
+
+func GetActiveFault(ownerName, eventName, srcObjUUID string) (*objects.ActiveFault, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_ACTIVE_FAULT,
+		Data: interface{}(&server.GetActiveInArgs{
+			OwnerName:  ownerName,
+			EventName:  eventName,
+			SrcObjUUID: srcObjUUID,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetActiveFaultOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetActiveFault")
+}
+
+func GetActiveAlarm(ownerName, eventName, srcObjUUID string) (*objects.ActiveAlarm, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_ACTIVE_ALARM,
+		Data: interface{}(&server.GetActiveInArgs{
+			OwnerName:  ownerName,
+			EventName:  eventName,
+			SrcObjUUID: srcObjUUID,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetActiveAlarmOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetActiveAlarm")
+}
+
 func FaultEnableAction(cfg *objects.FaultEnable) (bool, error) {
 	svr.ReqChan <- &server.ServerRequest{
 		Op: server.FAULT_ENABLE_ACTION,
@@ -98,3 +230,431 @@ func FaultClearAction(cfg *objects.FaultClear) (bool, error) {
 	}
 	return false, errors.New("Error: Invalid response recevied from server during Executing Fault Clear Action")
 }
+
+func AlarmAckAction(cfg *objects.AlarmAck) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.ALARM_ACK_ACTION,
+		Data: interface{}(&server.AlarmAckActionInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.AlarmAckActionOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Executing Alarm Ack Action")
+}
+
+func AlarmShelveAction(cfg *objects.AlarmShelve) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.ALARM_SHELVE_ACTION,
+		Data: interface{}(&server.AlarmShelveActionInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.AlarmShelveActionOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Executing Alarm Shelve Action")
+}
+
+func CreateFMgrGlobal(cfg *objects.FMgrGlobal) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.CREATE_FMGR_GLOBAL,
+		Data: interface{}(&server.CreateFMgrGlobalInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.FMgrGlobalOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Create FMgrGlobal")
+}
+
+func UpdateFMgrGlobal(oldCfg, newCfg *objects.FMgrGlobal, attrset []bool) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.UPDATE_FMGR_GLOBAL,
+		Data: interface{}(&server.UpdateFMgrGlobalInArgs{
+			OldCfg:  oldCfg,
+			NewCfg:  newCfg,
+			AttrSet: attrset,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.FMgrGlobalOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Update FMgrGlobal")
+}
+
+func CreateEventHoldTime(cfg *objects.EventHoldTime) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.CREATE_EVENT_HOLD_TIME,
+		Data: interface{}(&server.CreateEventHoldTimeInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.EventHoldTimeOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Create EventHoldTime")
+}
+
+func UpdateEventHoldTime(oldCfg, newCfg *objects.EventHoldTime, attrset []bool) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.UPDATE_EVENT_HOLD_TIME,
+		Data: interface{}(&server.UpdateEventHoldTimeInArgs{
+			OldCfg:  oldCfg,
+			NewCfg:  newCfg,
+			AttrSet: attrset,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.EventHoldTimeOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Update EventHoldTime")
+}
+
+func DeleteEventHoldTime(cfg *objects.EventHoldTime) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.DELETE_EVENT_HOLD_TIME,
+		Data: interface{}(&server.DeleteEventHoldTimeInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.EventHoldTimeOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Delete EventHoldTime")
+}
+
+func CreateAlarmEscalation(cfg *objects.AlarmEscalation) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.CREATE_ALARM_ESCALATION,
+		Data: interface{}(&server.CreateAlarmEscalationInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.AlarmEscalationOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Create Alarm Escalation")
+}
+
+func UpdateAlarmEscalation(oldCfg, newCfg *objects.AlarmEscalation, attrset []bool) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.UPDATE_ALARM_ESCALATION,
+		Data: interface{}(&server.UpdateAlarmEscalationInArgs{
+			OldCfg:  oldCfg,
+			NewCfg:  newCfg,
+			AttrSet: attrset,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.AlarmEscalationOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Update Alarm Escalation")
+}
+
+func DeleteAlarmEscalation(cfg *objects.AlarmEscalation) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.DELETE_ALARM_ESCALATION,
+		Data: interface{}(&server.DeleteAlarmEscalationInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.AlarmEscalationOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Delete Alarm Escalation")
+}
+
+func CreateSnmpTrapReceiver(cfg *objects.SnmpTrapReceiver) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.CREATE_SNMP_TRAP_RECEIVER,
+		Data: interface{}(&server.CreateSnmpTrapReceiverInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.SnmpTrapReceiverOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Create SnmpTrapReceiver")
+}
+
+func UpdateSnmpTrapReceiver(oldCfg, newCfg *objects.SnmpTrapReceiver, attrset []bool) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.UPDATE_SNMP_TRAP_RECEIVER,
+		Data: interface{}(&server.UpdateSnmpTrapReceiverInArgs{
+			OldCfg:  oldCfg,
+			NewCfg:  newCfg,
+			AttrSet: attrset,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.SnmpTrapReceiverOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Update SnmpTrapReceiver")
+}
+
+func DeleteSnmpTrapReceiver(cfg *objects.SnmpTrapReceiver) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.DELETE_SNMP_TRAP_RECEIVER,
+		Data: interface{}(&server.DeleteSnmpTrapReceiverInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.SnmpTrapReceiverOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Delete SnmpTrapReceiver")
+}
+
+func CreateSyslogServer(cfg *objects.SyslogServer) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.CREATE_SYSLOG_SERVER,
+		Data: interface{}(&server.CreateSyslogServerInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.SyslogServerOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Create SyslogServer")
+}
+
+func UpdateSyslogServer(oldCfg, newCfg *objects.SyslogServer, attrset []bool) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.UPDATE_SYSLOG_SERVER,
+		Data: interface{}(&server.UpdateSyslogServerInArgs{
+			OldCfg:  oldCfg,
+			NewCfg:  newCfg,
+			AttrSet: attrset,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.SyslogServerOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Update SyslogServer")
+}
+
+func DeleteSyslogServer(cfg *objects.SyslogServer) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.DELETE_SYSLOG_SERVER,
+		Data: interface{}(&server.DeleteSyslogServerInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.SyslogServerOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Delete SyslogServer")
+}
+
+func EventsReloadAction(cfg *objects.EventsReload) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.EVENTS_RELOAD_ACTION,
+		Data: interface{}(&server.EventsReloadActionInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.EventsReloadActionOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Executing EventsReload Action")
+}
+
+func GetEventsReloadState(vrf string) (*objects.EventsReloadState, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_EVENTS_RELOAD_STATE,
+		Data: interface{}(&server.GetEventsReloadStateInArgs{
+			Vrf: vrf,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetEventsReloadStateOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetEventsReloadState")
+}
+
+func GetAlarmSummary(vrf string) (*objects.AlarmSummary, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_ALARM_SUMMARY,
+		Data: interface{}(&server.GetAlarmSummaryInArgs{
+			Vrf: vrf,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetAlarmSummaryOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetAlarmSummary")
+}
+
+func FaultInjectAction(cfg *objects.FaultInject) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.FAULT_INJECT_ACTION,
+		Data: interface{}(&server.FaultInjectActionInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.FaultInjectActionOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Executing Fault Inject Action")
+}
+
+func HistoryExportAction(cfg *objects.HistoryExport) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.HISTORY_EXPORT_ACTION,
+		Data: interface{}(&server.HistoryExportActionInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.HistoryExportActionOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Executing History Export Action")
+}
+
+func GetHistoryExportState(fileName string) (*objects.HistoryExportState, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_HISTORY_EXPORT_STATE,
+		Data: interface{}(&server.GetHistoryExportStateInArgs{
+			FileName: fileName,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetHistoryExportStateOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetHistoryExportState")
+}
+
+func GetBulkHistoryExportState(fromIdx, count int) (*objects.HistoryExportStateGetInfo, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_BULK_HISTORY_EXPORT_STATE,
+		Data: interface{}(&server.GetBulkInArgs{
+			FromIdx: fromIdx,
+			Count:   count,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetBulkHistoryExportStateOutArgs); ok {
+		return retObj.BulkInfo, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetBulkHistoryExportState")
+}
+
+func CreateMaintenanceWindow(cfg *objects.MaintenanceWindow) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.CREATE_MAINTENANCE_WINDOW,
+		Data: interface{}(&server.CreateMaintenanceWindowInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.MaintenanceWindowOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Create Maintenance Window")
+}
+
+func UpdateMaintenanceWindow(oldCfg, newCfg *objects.MaintenanceWindow, attrset []bool) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.UPDATE_MAINTENANCE_WINDOW,
+		Data: interface{}(&server.UpdateMaintenanceWindowInArgs{
+			OldCfg:  oldCfg,
+			NewCfg:  newCfg,
+			AttrSet: attrset,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.MaintenanceWindowOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Update Maintenance Window")
+}
+
+func DeleteMaintenanceWindow(cfg *objects.MaintenanceWindow) (bool, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.DELETE_MAINTENANCE_WINDOW,
+		Data: interface{}(&server.DeleteMaintenanceWindowInArgs{
+			Config: cfg,
+		}),
+	}
+
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.MaintenanceWindowOutArgs); ok {
+		return retObj.RetVal, retObj.Err
+	}
+	return false, errors.New("Error: Invalid response recevied from server during Delete Maintenance Window")
+}
+
+func GetEventIngestState(ownerName string) (*objects.EventIngestState, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_EVENT_INGEST_STATE,
+		Data: interface{}(&server.GetEventIngestStateInArgs{
+			OwnerName: ownerName,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetEventIngestStateOutArgs); ok {
+		return retObj.Obj, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetEventIngestState")
+}
+
+func GetBulkEventIngestState(fromIdx, count int) (*objects.EventIngestStateGetInfo, error) {
+	svr.ReqChan <- &server.ServerRequest{
+		Op: server.GET_BULK_EVENT_INGEST_STATE,
+		Data: interface{}(&server.GetBulkInArgs{
+			FromIdx: fromIdx,
+			Count:   count,
+		}),
+	}
+	ret := <-svr.ReplyChan
+	if retObj, ok := ret.(*server.GetBulkEventIngestStateOutArgs); ok {
+		return retObj.BulkInfo, retObj.Err
+	}
+	return nil, errors.New("Error: Invalid response recevied from server during GetBulkEventIngestState")
+}
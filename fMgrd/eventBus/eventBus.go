@@ -0,0 +1,115 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+// Package eventBus abstracts the transport used by fMgrd to receive the
+// daemon events and to publish the faults and alarms.
+package eventBus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"utils/logging"
+)
+
+const (
+	EVENT_BUS_CONFIG_FILE = "/opt/flexswitch/fMgrd/eventBus.json"
+	REDIS_BUS             = "redis"
+	INPROC_BUS            = "inproc"
+	DEFAULT_REDIS_ADDRESS = ":6379"
+)
+
+type Message struct {
+	Channel string
+	Data    []byte
+}
+
+type Publisher interface {
+	Connect() error
+	Publish(channel string, msg []byte) error
+}
+
+type Subscriber interface {
+	Connect() error
+	Subscribe(channels ...string) error
+	Unsubscribe(channels ...string) error
+	// Receive blocks till a message is received on one of the subscribed
+	// channels, an error is returned once the bus is closed
+	Receive() (Message, error)
+}
+
+type EventBus interface {
+	Publisher
+	Subscriber
+	Close() error
+}
+
+type EventBusConfig struct {
+	Type     string // redis, the config DB of fMgrd is in Redis regardless
+	Address  string
+	Password string
+	DB       int
+}
+
+// LoadEventBusConfig reads the bus configuration, Redis on the default
+// address is used when the file does not exist
+func LoadEventBusConfig(fileName string) (EventBusConfig, error) {
+	cfg := EventBusConfig{
+		Type:    REDIS_BUS,
+		Address: DEFAULT_REDIS_ADDRESS,
+	}
+	bytes, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	err = json.Unmarshal(bytes, &cfg)
+	if err != nil {
+		return cfg, errors.New(fmt.Sprintln("Error parsing", fileName, err))
+	}
+	cfg.Type = strings.ToLower(cfg.Type)
+	if cfg.Type == "" {
+		cfg.Type = REDIS_BUS
+	}
+	if cfg.Type == REDIS_BUS && cfg.Address == "" {
+		cfg.Address = DEFAULT_REDIS_ADDRESS
+	}
+	return cfg, nil
+}
+
+func NewEventBus(logger logging.LoggerIntf, cfg EventBusConfig) (EventBus, error) {
+	switch cfg.Type {
+	case REDIS_BUS:
+		return NewRedisBus(logger, cfg.Address, cfg.Password, cfg.DB), nil
+	case INPROC_BUS:
+		// fMgrd reads its config DB out of Redis, running it without Redis
+		// is not supported
+		return nil, errors.New(fmt.Sprintln("Event bus type", cfg.Type, "is meant for tests only"))
+	}
+	return nil, errors.New(fmt.Sprintln("Unsupported event bus type", cfg.Type))
+}
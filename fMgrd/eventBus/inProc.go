@@ -0,0 +1,111 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package eventBus
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	INPROC_QUEUE_SIZE = 1000
+)
+
+// InProcBus delivers the published messages to the subscribers within the
+// same process. It is meant for tests embedding fault manager along with a
+// fake config DB, it can not be selected through eventBus.json as fMgrd
+// still needs Redis for its config DB.
+type InProcBus struct {
+	mutex    sync.RWMutex
+	channels map[string]bool
+	msgCh    chan Message
+	closed   bool
+}
+
+func NewInProcBus() *InProcBus {
+	return &InProcBus{
+		channels: make(map[string]bool),
+		msgCh:    make(chan Message, INPROC_QUEUE_SIZE),
+	}
+}
+
+func (bus *InProcBus) Connect() error {
+	return nil
+}
+
+// Publish drops the message when nobody subscribed to the channel, like
+// Redis does, and when the subscriber is not keeping up so that the
+// publisher never blocks
+func (bus *InProcBus) Publish(channel string, msg []byte) error {
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+	if bus.closed {
+		return errors.New("In process event bus is closed")
+	}
+	if !bus.channels[channel] {
+		return nil
+	}
+	select {
+	case bus.msgCh <- Message{Channel: channel, Data: msg}:
+		return nil
+	default:
+		return errors.New("In process event bus queue is full, dropping message on " + channel)
+	}
+}
+
+func (bus *InProcBus) Subscribe(channels ...string) error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	for _, channel := range channels {
+		bus.channels[channel] = true
+	}
+	return nil
+}
+
+func (bus *InProcBus) Unsubscribe(channels ...string) error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	for _, channel := range channels {
+		delete(bus.channels, channel)
+	}
+	return nil
+}
+
+func (bus *InProcBus) Receive() (Message, error) {
+	msg, ok := <-bus.msgCh
+	if !ok {
+		return msg, errors.New("In process event bus is closed")
+	}
+	return msg, nil
+}
+
+func (bus *InProcBus) Close() error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if !bus.closed {
+		bus.closed = true
+		close(bus.msgCh)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package eventBus
+
+import (
+	"errors"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	"sync"
+	"time"
+	"utils/logging"
+)
+
+const (
+	REDIS_DIAL_INTERVAL = 2 * time.Second
+)
+
+type RedisBus struct {
+	logger   logging.LoggerIntf
+	address  string
+	password string
+	db       int
+	mutex    sync.Mutex
+	pubHdl   redis.Conn
+	subHdl   *redis.PubSubConn
+}
+
+func NewRedisBus(logger logging.LoggerIntf, address, password string, db int) *RedisBus {
+	return &RedisBus{
+		logger:   logger,
+		address:  address,
+		password: password,
+		db:       db,
+	}
+}
+
+func (bus *RedisBus) dial() redis.Conn {
+	retryCount := 0
+	ticker := time.NewTicker(REDIS_DIAL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		conn, err := redis.Dial("tcp", bus.address, redis.DialPassword(bus.password), redis.DialDatabase(bus.db))
+		if err == nil {
+			return conn
+		}
+		retryCount += 1
+		if retryCount%100 == 0 {
+			bus.logger.Err(fmt.Sprintln("Failed to dail out to Redis server", bus.address, ". Retrying connection. Num of retries = ", retryCount))
+		}
+		<-ticker.C
+	}
+}
+
+// Connect dials the publishing and the subscribing connections, it is a
+// no-op once connected
+func (bus *RedisBus) Connect() error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.pubHdl != nil {
+		return nil
+	}
+	bus.pubHdl = bus.dial()
+	bus.subHdl = &redis.PubSubConn{Conn: bus.dial()}
+	return nil
+}
+
+func (bus *RedisBus) Publish(channel string, msg []byte) error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.pubHdl == nil {
+		return errors.New("Redis event bus is not connected")
+	}
+	_, err := bus.pubHdl.Do("PUBLISH", channel, msg)
+	if err != nil {
+		bus.logger.Err(fmt.Sprintln("Error publishing on", channel, err))
+	}
+	return err
+}
+
+func (bus *RedisBus) getSubHdl() (*redis.PubSubConn, error) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.subHdl == nil {
+		return nil, errors.New("Redis event bus is not connected")
+	}
+	return bus.subHdl, nil
+}
+
+func (bus *RedisBus) Subscribe(channels ...string) error {
+	subHdl, err := bus.getSubHdl()
+	if err != nil {
+		return err
+	}
+	var args []interface{}
+	for _, channel := range channels {
+		args = append(args, channel)
+	}
+	return subHdl.Subscribe(args...)
+}
+
+func (bus *RedisBus) Unsubscribe(channels ...string) error {
+	subHdl, err := bus.getSubHdl()
+	if err != nil {
+		return err
+	}
+	var args []interface{}
+	for _, channel := range channels {
+		args = append(args, channel)
+	}
+	return subHdl.Unsubscribe(args...)
+}
+
+func (bus *RedisBus) Receive() (Message, error) {
+	subHdl, err := bus.getSubHdl()
+	if err != nil {
+		return Message{}, err
+	}
+	for {
+		switch n := subHdl.Receive().(type) {
+		case redis.Message:
+			return Message{
+				Channel: n.Channel,
+				Data:    n.Data,
+			}, nil
+		case redis.Subscription:
+			if n.Count == 0 {
+				bus.logger.Err("Empty data Received")
+			}
+		case error:
+			return Message{}, n
+		}
+	}
+}
+
+func (bus *RedisBus) Close() error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.pubHdl == nil {
+		return nil
+	}
+	bus.pubHdl.Close()
+	err := bus.subHdl.Close()
+	bus.pubHdl = nil
+	bus.subHdl = nil
+	return err
+}
@@ -0,0 +1,207 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"sort"
+)
+
+// getActiveFaults returns the faults present in FaultMap, newest first
+func (fMgr *FaultManager) getActiveFaults() []FaultRBEntry {
+	var faults []FaultRBEntry
+	fMgr.FMapRWMutex.RLock()
+	fMgr.FRBRWMutex.RLock()
+	for _, fDataMapEnt := range fMgr.FaultMap {
+		for _, fDataEnt := range fDataMapEnt {
+			fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+			fault := fIntf.(FaultRBEntry)
+			if fault.FaultSeqNumber == fDataEnt.FaultSeqNumber {
+				faults = append(faults, fault)
+			}
+		}
+	}
+	fMgr.FRBRWMutex.RUnlock()
+	fMgr.FMapRWMutex.RUnlock()
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].FaultSeqNumber > faults[j].FaultSeqNumber
+	})
+	return faults
+}
+
+// getActiveAlarms returns the alarms present in AlarmMap, newest first
+func (fMgr *FaultManager) getActiveAlarms() []AlarmRBEntry {
+	var alarms []AlarmRBEntry
+	fMgr.AMapRWMutex.RLock()
+	fMgr.ARBRWMutex.RLock()
+	for _, aDataMapEnt := range fMgr.AlarmMap {
+		for _, aDataEnt := range aDataMapEnt {
+			aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+			alarm := aIntf.(AlarmRBEntry)
+			if alarm.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+				alarms = append(alarms, alarm)
+			}
+		}
+	}
+	fMgr.ARBRWMutex.RUnlock()
+	fMgr.AMapRWMutex.RUnlock()
+	sort.Slice(alarms, func(i, j int) bool {
+		return alarms[i].AlarmSeqNumber > alarms[j].AlarmSeqNumber
+	})
+	return alarms
+}
+
+func (fMgr *FaultManager) GetActiveFaultObject(fault *FaultRBEntry) (obj objects.ActiveFault, err error) {
+	fObj, err := fMgr.GetFaultStateObject(fault)
+	if err != nil {
+		return obj, err
+	}
+	obj.OwnerId = fObj.OwnerId
+	obj.EventId = fObj.EventId
+	obj.OwnerName = fObj.OwnerName
+	obj.EventName = fObj.EventName
+	obj.SrcObjName = fObj.SrcObjName
+	obj.Description = fObj.Description
+	obj.OccuranceTime = fObj.OccuranceTime
+	obj.SrcObjKey = fObj.SrcObjKey
+	obj.SrcObjUUID = fObj.SrcObjUUID
+	return obj, nil
+}
+
+func (fMgr *FaultManager) GetActiveAlarmObject(alarm *AlarmRBEntry) (obj objects.ActiveAlarm, err error) {
+	aObj, err := fMgr.GetAlarmStateObject(alarm)
+	if err != nil {
+		return obj, err
+	}
+	obj.OwnerId = aObj.OwnerId
+	obj.EventId = aObj.EventId
+	obj.OwnerName = aObj.OwnerName
+	obj.EventName = aObj.EventName
+	obj.SrcObjName = aObj.SrcObjName
+	obj.Severity = aObj.Severity
+	obj.Description = aObj.Description
+	obj.OccuranceTime = aObj.OccuranceTime
+	obj.SrcObjKey = aObj.SrcObjKey
+	obj.SrcObjUUID = aObj.SrcObjUUID
+	obj.Acknowledged = aObj.Acknowledged
+	obj.AckedBy = aObj.AckedBy
+	obj.AckTime = aObj.AckTime
+	obj.AckNote = aObj.AckNote
+	obj.Flapping = aObj.Flapping
+	obj.FlapCount = aObj.FlapCount
+	obj.EscalationLevel = aObj.EscalationLevel
+	obj.Suppressed = aObj.Suppressed
+	obj.SuppressedBy = aObj.SuppressedBy
+	return obj, nil
+}
+
+func (fMgr *FaultManager) GetBulkActiveFault(fromIdx int, count int) (*objects.ActiveFaultGetInfo, error) {
+	var retObj objects.ActiveFaultGetInfo
+
+	faults := fMgr.getActiveFaults()
+	length := len(faults)
+	fList := make([]objects.ActiveFault, count)
+
+	var i int
+	var j int
+
+	for i, j = 0, fromIdx; i < count && j < length; j++ {
+		obj, err := fMgr.GetActiveFaultObject(&faults[j])
+		if err != nil {
+			continue
+		}
+		fList[i] = obj
+		i++
+	}
+	retObj.EndIdx = j
+	retObj.Count = i
+	if j < length {
+		retObj.More = true
+	}
+	retObj.List = fList
+	return &retObj, nil
+}
+
+func (fMgr *FaultManager) GetBulkActiveAlarm(fromIdx int, count int) (*objects.ActiveAlarmGetInfo, error) {
+	var retObj objects.ActiveAlarmGetInfo
+
+	alarms := fMgr.getActiveAlarms()
+	length := len(alarms)
+	aList := make([]objects.ActiveAlarm, count)
+
+	var i int
+	var j int
+
+	for i, j = 0, fromIdx; i < count && j < length; j++ {
+		obj, err := fMgr.GetActiveAlarmObject(&alarms[j])
+		if err != nil {
+			continue
+		}
+		aList[i] = obj
+		i++
+	}
+	retObj.EndIdx = j
+	retObj.Count = i
+	if j < length {
+		retObj.More = true
+	}
+	retObj.List = aList
+	return &retObj, nil
+}
+
+func (fMgr *FaultManager) GetActiveFault(ownerName, eventName, srcObjUUID string) (*objects.ActiveFault, error) {
+	evtKey, err := fMgr.getFaultEventKey(ownerName, eventName)
+	if err != nil {
+		return nil, err
+	}
+	for _, fault := range fMgr.getActiveFaults() {
+		if fault.OwnerId == evtKey.DaemonId && fault.EventId == evtKey.EventId && fault.SrcObjUUID == srcObjUUID {
+			obj, err := fMgr.GetActiveFaultObject(&fault)
+			if err != nil {
+				return nil, err
+			}
+			return &obj, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintln("Unable to find the active fault for", ownerName, eventName, srcObjUUID))
+}
+
+func (fMgr *FaultManager) GetActiveAlarm(ownerName, eventName, srcObjUUID string) (*objects.ActiveAlarm, error) {
+	evtKey, err := fMgr.getFaultEventKey(ownerName, eventName)
+	if err != nil {
+		return nil, err
+	}
+	for _, alarm := range fMgr.getActiveAlarms() {
+		if alarm.OwnerId == evtKey.DaemonId && alarm.EventId == evtKey.EventId && alarm.SrcObjUUID == srcObjUUID {
+			obj, err := fMgr.GetActiveAlarmObject(&alarm)
+			if err != nil {
+				return nil, err
+			}
+			return &obj, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintln("Unable to find the active alarm for", ownerName, eventName, srcObjUUID))
+}
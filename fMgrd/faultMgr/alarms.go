@@ -26,9 +26,9 @@ package faultMgr
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"infra/fMgrd/objects"
 	"time"
-	"utils/eventUtils"
 )
 
 func (fMgr *FaultManager) GetAlarmStateObject(alarm *AlarmRBEntry) (aObj objects.AlarmState, err error) {
@@ -38,14 +38,21 @@ func (fMgr *FaultManager) GetAlarmStateObject(alarm *AlarmRBEntry) (aObj objects
 		DaemonId: alarm.OwnerId,
 		EventId:  alarm.EventId,
 	}
-	fEnt, exist := fMgr.FaultEventMap[evtKey]
+	fEnt, exist := fMgr.getFaultEvent(evtKey)
 	if !exist {
 		return aObj, errors.New("Error finding the entry in AlarmRB")
 	}
 	aObj.OwnerName = fEnt.FaultOwnerName
 	aObj.EventName = fEnt.FaultEventName
 	aObj.SrcObjName = fEnt.FaultSrcObjName
-	aObj.Severity = fEnt.AlarmSeverity
+	aObj.Severity = getAlarmSeverity(alarm, fEnt)
+	aObj.EscalationLevel = int32(alarm.EscalationLevel)
+	aObj.Suppressed = alarm.Suppressed
+	aObj.OccurrenceCount = getOccurrenceCount(alarm.OccurrenceCount)
+	aObj.LastSeenTime = getLastSeenTime(alarm.LastSeenTime, alarm.OccuranceTime)
+	aObj.SeqNumber = int64(alarm.AlarmSeqNumber)
+	aObj.Injected = alarm.Injected
+	aObj.SuppressedBy = alarm.SuppressedBy
 	aObj.Description = alarm.Description
 	aObj.OccuranceTime = alarm.OccuranceTime.String()
 	aObj.SrcObjKey = alarm.SrcObjKey
@@ -58,14 +65,42 @@ func (fMgr *FaultManager) GetAlarmStateObject(alarm *AlarmRBEntry) (aObj objects
 		aObj.ResolutionTime = "N/A"
 		aObj.ResolutionReason = "N/A"
 	}
+	aObj.Acknowledged = alarm.Acknowledged
+	aObj.AckedBy = alarm.AckedBy
+	aObj.AckNote = alarm.AckNote
+	aObj.Flapping = alarm.Flapping
+	aObj.FlapCount = int32(alarm.FlapCount)
+	if alarm.AckTime.IsZero() {
+		aObj.AckTime = "N/A"
+	} else {
+		aObj.AckTime = alarm.AckTime.String()
+	}
 	return aObj, nil
 }
 
-func (fMgr *FaultManager) PublishAlarms(idx int) {
+func (fMgr *FaultManager) PublishAlarms(idx int, transition StateTransition) {
 	fMgr.ARBRWMutex.RLock()
 	aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(idx)
 	fMgr.ARBRWMutex.RUnlock()
-	alarm := aIntf.(AlarmRBEntry)
+	fMgr.publishAlarmEntry(aIntf.(AlarmRBEntry), transition)
+}
+
+// publishAlarmEntry publishes a copy of the alarm taken under ARBRWMutex.
+// Callers publishing after releasing the locks use it, as the ring buffer
+// index of the alarm may be renumbered by a purge in the meantime.
+func (fMgr *FaultManager) publishAlarmEntry(alarm AlarmRBEntry, transition StateTransition) {
+	if alarm.Suppressed {
+		// Covered by the alarm of the parent event
+		return
+	}
+	evtKey := EventKey{
+		DaemonId: alarm.OwnerId,
+		EventId:  alarm.EventId,
+	}
+	if !alarm.Resolved && fMgr.isAlarmShelved(evtKey, alarm.SrcObjUUID) {
+		fMgr.logger.Debug("Alarm is shelved, hence withholding alarm publication for", evtKey, alarm.SrcObjUUID)
+		return
+	}
 	aObj, err := fMgr.GetAlarmStateObject(&alarm)
 	if err != nil {
 		fMgr.logger.Err("Error Fetching the fault state object", err)
@@ -73,12 +108,17 @@ func (fMgr *FaultManager) PublishAlarms(idx int) {
 	}
 	msg, _ := json.Marshal(aObj)
 	channel := aObj.OwnerName + "Alarms"
-	fMgr.AlarmPubHdl.Publish("PUBLISH", channel, msg)
+	fMgr.AlarmPubHdl.Publish(channel, msg)
+	fMgr.exportAlarm(aObj, transition)
 }
 
-func (fMgr *FaultManager) GetBulkAlarmState(fromIdx int, count int) (*objects.AlarmStateGetInfo, error) {
+func (fMgr *FaultManager) GetBulkAlarmState(fromIdx int, count int, filter *objects.StateFilter) (*objects.AlarmStateGetInfo, error) {
 	var retObj objects.AlarmStateGetInfo
 
+	sFilter, err := compileStateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
 	fMgr.ARBRWMutex.RLock()
 	alarms := fMgr.AlarmRB.GetListOfEntriesFromRingBuffer()
 	fMgr.ARBRWMutex.RUnlock()
@@ -91,6 +131,9 @@ func (fMgr *FaultManager) GetBulkAlarmState(fromIdx int, count int) (*objects.Al
 	for i, j = 0, fromIdx; i < count && j < length; j++ {
 		aIntf := alarms[length-j-1]
 		alarm := aIntf.(AlarmRBEntry)
+		if !fMgr.matchAlarm(sFilter, &alarm) {
+			continue
+		}
 		aObj, err := fMgr.GetAlarmStateObject(&alarm)
 		if err != nil {
 			continue
@@ -98,6 +141,14 @@ func (fMgr *FaultManager) GetBulkAlarmState(fromIdx int, count int) (*objects.Al
 		aState[i] = aObj
 		i++
 	}
+	// Skip the entries filtered out so that More is only set when there
+	// is a matching entry left for the next page
+	for ; j < length; j++ {
+		alarm := alarms[length-j-1].(AlarmRBEntry)
+		if fMgr.matchAlarm(sFilter, &alarm) {
+			break
+		}
+	}
 	retObj.EndIdx = j
 	retObj.Count = i
 	if j != length {
@@ -108,13 +159,20 @@ func (fMgr *FaultManager) GetBulkAlarmState(fromIdx int, count int) (*objects.Al
 	return &retObj, nil
 }
 
-func (fMgr *FaultManager) StartAlarmTimer(evt eventUtils.Event) *time.Timer {
-	evtKey := EventKey{
-		DaemonId: int(evt.OwnerId),
-		EventId:  int(evt.EvtId),
-	}
+func (fMgr *FaultManager) StartAlarmTimer(evtKey EventKey, fObjKey FaultObjKey, objKey, uuid string, attrs map[string]string, description string, injected bool) Timer {
+	return fMgr.startAlarmTimerWithDelay(fMgr.getFaultToAlarmTransitionTime(evtKey), evtKey, fObjKey, objKey, uuid, attrs, description, injected)
+}
 
+func (fMgr *FaultManager) startAlarmTimerWithDelay(delay time.Duration, evtKey EventKey, fObjKey FaultObjKey, objKey, uuid string, attrs map[string]string, description string, injected bool) Timer {
 	alarmFunc := func() {
+		if fMgr.isAlarmShelved(evtKey, uuid) {
+			fMgr.logger.Debug("Alarm is shelved, hence withholding alarm generation for", evtKey, uuid)
+			return
+		}
+		if fMgr.isInMaintenance(evtKey, objKey) {
+			fMgr.logger.Debug("Object is in maintenance, hence withholding alarm generation for", evtKey, objKey)
+			return
+		}
 		fMgr.AMapRWMutex.Lock()
 		if fMgr.AlarmMap[evtKey] == nil {
 			fMgr.logger.Debug("Alarm Database does not exist, hence creating one")
@@ -122,21 +180,20 @@ func (fMgr *FaultManager) StartAlarmTimer(evt eventUtils.Event) *time.Timer {
 		}
 
 		aDataMapEnt, _ := fMgr.AlarmMap[evtKey]
-		fObjKey, fObjKeyUUId, objKey, err := fMgr.generateFaultObjKey(evt.OwnerName, evt.SrcObjName, evt.SrcObjKey)
-		if err != nil {
-			fMgr.logger.Err("Fault Obj key, hence skipping alarm generation")
-			fMgr.AMapRWMutex.Unlock()
-			return
-		}
-
 		aDataEnt, exist := aDataMapEnt[fObjKey]
 		if exist {
 			fMgr.logger.Err("Alarm Data entry already exist, hence skipping this")
 			fMgr.AMapRWMutex.Unlock()
 			return
 		}
-		aDataEnt.AlarmListIdx = fMgr.AddAlarmEntryInRB(evt, objKey, fObjKeyUUId)
-		fMgr.PublishAlarms(aDataEnt.AlarmListIdx)
+		aDataEnt.AlarmListIdx = fMgr.AddAlarmEntryInRB(evtKey, objKey, uuid, attrs, description, injected)
+		fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_RAISED)
+		fMgr.ARBRWMutex.RLock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		fMgr.ARBRWMutex.RUnlock()
+		aRBData := aIntf.(AlarmRBEntry)
+		fMgr.updateAlarmSummary(&aRBData, 1)
+		aDataEnt.EscalationTimer = fMgr.StartEscalationTimer(evtKey, fObjKey, &aRBData)
 		aDataEnt.AlarmSeqNumber = fMgr.AlarmSeqNumber
 		fMgr.AlarmSeqNumber++
 		aDataMapEnt[fObjKey] = aDataEnt
@@ -144,48 +201,41 @@ func (fMgr *FaultManager) StartAlarmTimer(evt eventUtils.Event) *time.Timer {
 		fMgr.AMapRWMutex.Unlock()
 	}
 
-	return time.AfterFunc(fMgr.FaultToAlarmTransitionTime, alarmFunc)
+	return fMgr.Clock.AfterFunc(delay, alarmFunc)
 }
 
-func (fMgr *FaultManager) AddAlarmEntryInRB(evt eventUtils.Event, objKey, uuid string) int {
+func (fMgr *FaultManager) AddAlarmEntryInRB(evtKey EventKey, objKey, uuid string, attrs map[string]string, description string, injected bool) int {
+	fEnt, _ := fMgr.getFaultEvent(evtKey)
 	aRBEnt := AlarmRBEntry{
-		OwnerId:        int(evt.OwnerId),
-		EventId:        int(evt.EvtId),
-		OccuranceTime:  time.Now(),
-		SrcObjKey:      objKey,
-		SrcObjUUID:     uuid,
-		AlarmSeqNumber: fMgr.AlarmSeqNumber,
-		Description:    evt.Description,
+		OwnerId:         evtKey.DaemonId,
+		EventId:         evtKey.EventId,
+		OccuranceTime:   fMgr.Clock.Now(),
+		SrcObjKey:       objKey,
+		SrcObjUUID:      uuid,
+		AlarmSeqNumber:  fMgr.AlarmSeqNumber,
+		Description:     description,
+		Severity:        fEnt.AlarmSeverity,
+		SrcObjAttrs:     attrs,
+		OccurrenceCount: 1,
+		Injected:        injected,
 	}
+	fMgr.markSuppression(evtKey, &aRBEnt)
+	return fMgr.insertAlarmEntryInRB(aRBEnt)
+}
 
+// Caller is expected to hold AMapRWMutex
+func (fMgr *FaultManager) insertAlarmEntryInRB(aRBEnt AlarmRBEntry) int {
+	retention := fMgr.getHistoryRetention()
 	fMgr.ARBRWMutex.Lock()
+	fMgr.makeRoomInAlarmRB(retention)
 	idx, _ := fMgr.AlarmRB.InsertIntoRingBuffer(aRBEnt)
+	fMgr.AlarmRBCount++
+	fMgr.History.RecordAlarm(aRBEnt)
 	fMgr.ARBRWMutex.Unlock()
 	return idx
 }
 
-func (fMgr *FaultManager) StartAlarmRemoveTimer(evt eventUtils.Event, reason Reason) *time.Timer {
-	evtKey := EventKey{
-		DaemonId: int(evt.OwnerId),
-		EventId:  int(evt.EvtId),
-	}
-
-	cFEnt, exist := fMgr.NonFaultEventMap[evtKey]
-	if !exist {
-		fMgr.logger.Err("Error finding the fault for fault clearing event")
-		return nil
-	}
-	fEvtKey := EventKey{
-		DaemonId: cFEnt.FaultOwnerId,
-		EventId:  cFEnt.FaultEventId,
-	}
-
-	fObjKey, _, _, err := fMgr.generateFaultObjKey(evt.OwnerName, evt.SrcObjName, evt.SrcObjKey)
-	if err != nil {
-		fMgr.logger.Err("Error generating fault object key")
-		return nil
-	}
-
+func (fMgr *FaultManager) StartAlarmRemoveTimer(fEvtKey EventKey, fObjKey FaultObjKey, reason Reason) Timer {
 	alarmFunc := func() {
 		fMgr.AMapRWMutex.Lock()
 		aDataMapEnt, exist := fMgr.AlarmMap[fEvtKey]
@@ -200,26 +250,33 @@ func (fMgr *FaultManager) StartAlarmRemoveTimer(evt eventUtils.Event, reason Rea
 			fMgr.AMapRWMutex.Unlock()
 			return
 		}
+		fMgr.ARBRWMutex.Lock()
 		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
 		aRBData := aIntf.(AlarmRBEntry)
 		if aRBData.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
-			aRBData.ResolutionTime = time.Now()
+			aRBData.ResolutionTime = fMgr.Clock.Now()
 			aRBData.ResolutionReason = reason
 			aRBData.Resolved = true
-			fMgr.ARBRWMutex.Lock()
+			fMgr.updateAlarmSummary(&aRBData, -1)
+			fMgr.stopEscalation(&aDataEnt)
 			fMgr.AlarmRB.UpdateEntryInRingBuffer(aRBData, aDataEnt.AlarmListIdx)
-			fMgr.ARBRWMutex.Unlock()
-			fMgr.PublishAlarms(aDataEnt.AlarmListIdx)
+			fMgr.History.RecordAlarm(aRBData)
+		}
+		fMgr.ARBRWMutex.Unlock()
+		if aRBData.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+			fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_CLEARED)
 			delete(aDataMapEnt, fObjKey)
 			fMgr.AlarmMap[fEvtKey] = aDataMapEnt
+			fMgr.reevaluateSuppressedAlarms(fEvtKey, &aRBData)
 		}
 		fMgr.AMapRWMutex.Unlock()
 	}
 
-	return time.AfterFunc(fMgr.AlarmTransitionTime, alarmFunc)
+	return fMgr.Clock.AfterFunc(fMgr.getAlarmTransitionTime(fEvtKey), alarmFunc)
 }
 
 func (fMgr *FaultManager) ClearExistingAlarms(evtKey EventKey, uuid string, reason Reason) {
+	var clearedList []AlarmRBEntry
 	fMgr.AMapRWMutex.Lock()
 	aDataMapEnt, exist := fMgr.AlarmMap[evtKey]
 	if !exist {
@@ -232,10 +289,13 @@ func (fMgr *FaultManager) ClearExistingAlarms(evtKey EventKey, uuid string, reas
 		aRBData := aIntf.(AlarmRBEntry)
 		if aRBData.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
 			if uuid == "" || uuid == aRBData.SrcObjUUID {
-				aRBData.ResolutionTime = time.Now()
+				aRBData.ResolutionTime = fMgr.Clock.Now()
 				aRBData.ResolutionReason = reason
 				aRBData.Resolved = true
+				fMgr.updateAlarmSummary(&aRBData, -1)
+				fMgr.stopEscalation(&aDataEnt)
 				fMgr.AlarmRB.UpdateEntryInRingBuffer(aRBData, aDataEnt.AlarmListIdx)
+				fMgr.History.RecordAlarm(aRBData)
 				if aDataEnt.RemoveAlarmTimer != nil {
 					aDataEnt.RemoveAlarmTimer.Stop()
 				}
@@ -245,12 +305,139 @@ func (fMgr *FaultManager) ClearExistingAlarms(evtKey EventKey, uuid string, reas
 		fMgr.ARBRWMutex.Unlock()
 		if aRBData.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
 			if uuid == "" || uuid == aRBData.SrcObjUUID {
-				fMgr.PublishAlarms(aDataEnt.AlarmListIdx)
+				fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_CLEARED)
+				clearedList = append(clearedList, aRBData)
 			}
 		}
 	}
 	if len(aDataMapEnt) == 0 {
 		delete(fMgr.AlarmMap, evtKey)
 	}
+	for idx := range clearedList {
+		fMgr.reevaluateSuppressedAlarms(evtKey, &clearedList[idx])
+	}
 	fMgr.AMapRWMutex.Unlock()
 }
+
+func (fMgr *FaultManager) AlarmAckAction(config *objects.AlarmAck) (retVal bool, err error) {
+	fMgr.PauseEventProcessCh <- true
+	<-fMgr.PauseEventProcessAckCh
+	evtKeyStr := EventKeyStr{
+		OwnerName: config.OwnerName,
+		EventName: config.EventName,
+	}
+	evtKey, exist := fMgr.getEventKey(evtKeyStr)
+	if !exist {
+		err = errors.New("Unable to find the corresponding event")
+	} else if config.SrcObjUUID == "" {
+		err = errors.New("SrcObjUUID of the alarm to be acknowledged is required")
+	} else {
+		if _, exist := fMgr.getFaultEvent(evtKey); !exist {
+			err = errors.New("Unable to find the corresponding faulty event")
+		} else {
+			retVal, err = fMgr.ackExistingAlarms(evtKey, config)
+		}
+	}
+	fMgr.PauseEventProcessCh <- true
+	return retVal, err
+}
+
+func (fMgr *FaultManager) ackExistingAlarms(evtKey EventKey, config *objects.AlarmAck) (bool, error) {
+	var ackedList []AlarmRBEntry
+	fMgr.AMapRWMutex.RLock()
+	aDataMapEnt, _ := fMgr.AlarmMap[evtKey]
+	for _, aDataEnt := range aDataMapEnt {
+		fMgr.ARBRWMutex.Lock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		aRBData := aIntf.(AlarmRBEntry)
+		if aRBData.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+			if config.SrcObjUUID == aRBData.SrcObjUUID {
+				aRBData.Acknowledged = config.Ack
+				if config.Ack {
+					aRBData.AckedBy = config.AckedBy
+					aRBData.AckNote = config.Note
+					aRBData.AckTime = fMgr.Clock.Now()
+				} else {
+					aRBData.AckedBy = ""
+					aRBData.AckNote = ""
+					aRBData.AckTime = time.Time{}
+				}
+				fMgr.AlarmRB.UpdateEntryInRingBuffer(aRBData, aDataEnt.AlarmListIdx)
+				fMgr.History.RecordAlarm(aRBData)
+				ackedList = append(ackedList, aRBData)
+			}
+		}
+		fMgr.ARBRWMutex.Unlock()
+	}
+	fMgr.AMapRWMutex.RUnlock()
+	if len(ackedList) == 0 {
+		return false, errors.New("Unable to find the corresponding active alarm")
+	}
+	for _, alarm := range ackedList {
+		fMgr.publishAlarmEntry(alarm, TRANSITION_NONE)
+	}
+	return true, nil
+}
+
+// GetAlarmState returns the active alarm of the event for the source object,
+// falling back to its most recent alarm in the alarm database. SrcObjUUID
+// can be left empty only if a single source object has alarms of the event.
+func (fMgr *FaultManager) GetAlarmState(ownerId, eventId int, ownerName, eventName, srcObjName, srcObjUUID string) (*objects.AlarmState, error) {
+	evtKey, err := fMgr.getStateEventKey(ownerId, eventId, ownerName, eventName, srcObjName)
+	if err != nil {
+		return nil, err
+	}
+
+	var alarm AlarmRBEntry
+	found := false
+	ambiguous := false
+	fMgr.AMapRWMutex.RLock()
+	fMgr.ARBRWMutex.RLock()
+	for _, aDataEnt := range fMgr.AlarmMap[evtKey] {
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		aEnt := aIntf.(AlarmRBEntry)
+		if aEnt.AlarmSeqNumber != aDataEnt.AlarmSeqNumber ||
+			(srcObjUUID != "" && aEnt.SrcObjUUID != srcObjUUID) {
+			continue
+		}
+		if found {
+			ambiguous = true
+			break
+		}
+		alarm = aEnt
+		found = true
+	}
+	if !found {
+		view := newRBView(fMgr.AlarmRB, fMgr.AlarmRBCount, alarmSeqOf)
+		for idx := view.length - 1; idx >= 0; idx-- {
+			aEnt := view.entryAt(idx).(AlarmRBEntry)
+			if aEnt.OwnerId != evtKey.DaemonId || aEnt.EventId != evtKey.EventId ||
+				(srcObjUUID != "" && aEnt.SrcObjUUID != srcObjUUID) {
+				continue
+			}
+			if !found {
+				alarm = aEnt
+				found = true
+				if srcObjUUID != "" {
+					break
+				}
+			} else if aEnt.SrcObjUUID != alarm.SrcObjUUID {
+				ambiguous = true
+				break
+			}
+		}
+	}
+	fMgr.ARBRWMutex.RUnlock()
+	fMgr.AMapRWMutex.RUnlock()
+	if ambiguous {
+		return nil, errors.New(fmt.Sprintln("Multiple source objects have alarms for", ownerName, eventName, "SrcObjUUID is required"))
+	}
+	if !found {
+		return nil, errors.New(fmt.Sprintln("Unable to find the alarm state for", ownerName, eventName, srcObjName, srcObjUUID))
+	}
+	aObj, err := fMgr.GetAlarmStateObject(&alarm)
+	if err != nil {
+		return nil, err
+	}
+	return &aObj, nil
+}
@@ -0,0 +1,307 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_FAULT_TO_ALARM_TRANSITION_TIME = time.Duration(3) * time.Second
+	DEFAULT_ALARM_TRANSITION_TIME          = time.Duration(3) * time.Second
+)
+
+// Positions of the FMgrGlobal attributes in the attrset of an update
+const (
+	FMGR_GLOBAL_VRF_ATTR = iota
+	FMGR_GLOBAL_FAULT_TO_ALARM_TRANSITION_TIME_ATTR
+	FMGR_GLOBAL_ALARM_TRANSITION_TIME_ATTR
+	FMGR_GLOBAL_FLAP_WINDOW_ATTR
+	FMGR_GLOBAL_FLAP_THRESHOLD_ATTR
+	FMGR_GLOBAL_FLAP_QUIET_PERIOD_ATTR
+	FMGR_GLOBAL_FAULT_HISTORY_SIZE_ATTR
+	FMGR_GLOBAL_ALARM_HISTORY_SIZE_ATTR
+	FMGR_GLOBAL_HISTORY_RETENTION_ATTR
+	FMGR_GLOBAL_HISTORY_EXPORT_DIR_ATTR
+	FMGR_GLOBAL_EVENT_OVERFLOW_POLICY_ATTR
+)
+
+type HoldTime struct {
+	FaultToAlarmTransitionTime time.Duration
+	AlarmTransitionTime        time.Duration
+}
+
+func validateHoldTime(faultToAlarmTime, alarmTime int32) error {
+	if faultToAlarmTime < 0 {
+		return errors.New("Invalid FaultToAlarmTransitionTime value provided")
+	}
+	if alarmTime < 0 {
+		return errors.New("Invalid AlarmTransitionTime value provided")
+	}
+	return nil
+}
+
+func validateFlapConfig(window, threshold, quietPeriod int32) error {
+	if threshold < FLAP_DETECTION_DISABLED {
+		return errors.New("Invalid FlapThreshold value provided")
+	}
+	if window < 0 {
+		return errors.New("Invalid FlapWindow value provided")
+	}
+	if quietPeriod < 0 {
+		return errors.New("Invalid FlapQuietPeriod value provided")
+	}
+	return nil
+}
+
+func validateHistoryConfig(faultHistorySize, alarmHistorySize, retention int32) error {
+	if faultHistorySize < 0 {
+		return errors.New("Invalid FaultHistorySize value provided")
+	}
+	if alarmHistorySize < 0 {
+		return errors.New("Invalid AlarmHistorySize value provided")
+	}
+	if retention < 0 {
+		return errors.New("Invalid HistoryRetention value provided")
+	}
+	return nil
+}
+
+// getGlobalHoldTime converts the globally configured hold time, 0 stands for
+// the default hold time
+func getGlobalHoldTime(holdTime int32, defaultHoldTime time.Duration) time.Duration {
+	if holdTime == 0 {
+		return defaultHoldTime
+	}
+	return time.Duration(holdTime) * time.Second
+}
+
+// getHistorySize returns the configured history capacity, 0 stands for the
+// default capacity
+func getHistorySize(size int32, defaultSize int) int {
+	if size == 0 {
+		return defaultSize
+	}
+	return int(size)
+}
+
+func (fMgr *FaultManager) getFaultToAlarmTransitionTime(evtKey EventKey) time.Duration {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	if holdTime, exist := fMgr.EventHoldTimeMap[evtKey]; exist {
+		return holdTime.FaultToAlarmTransitionTime
+	}
+	return fMgr.FaultToAlarmTransitionTime
+}
+
+func (fMgr *FaultManager) getAlarmTransitionTime(evtKey EventKey) time.Duration {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	if holdTime, exist := fMgr.EventHoldTimeMap[evtKey]; exist {
+		return holdTime.AlarmTransitionTime
+	}
+	return fMgr.AlarmTransitionTime
+}
+
+func (fMgr *FaultManager) CreateFMgrGlobal(config *objects.FMgrGlobal) (bool, error) {
+	err := validateHoldTime(config.FaultToAlarmTransitionTime, config.AlarmTransitionTime)
+	if err != nil {
+		return false, err
+	}
+	err = validateFlapConfig(config.FlapWindow, config.FlapThreshold, config.FlapQuietPeriod)
+	if err != nil {
+		return false, err
+	}
+	err = validateHistoryConfig(config.FaultHistorySize, config.AlarmHistorySize, config.HistoryRetention)
+	if err != nil {
+		return false, err
+	}
+	err = validateHistoryExportDir(config.HistoryExportDir)
+	if err != nil {
+		return false, err
+	}
+	err = validateOverflowPolicy(config.EventOverflowPolicy)
+	if err != nil {
+		return false, err
+	}
+	err = fMgr.setHistoryCapacity(getHistorySize(config.FaultHistorySize, FAULT_RB_CAPACITY), getHistorySize(config.AlarmHistorySize, ALARM_RB_CAPACITY))
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	fMgr.FaultToAlarmTransitionTime = getGlobalHoldTime(config.FaultToAlarmTransitionTime, DEFAULT_FAULT_TO_ALARM_TRANSITION_TIME)
+	fMgr.AlarmTransitionTime = getGlobalHoldTime(config.AlarmTransitionTime, DEFAULT_ALARM_TRANSITION_TIME)
+	fMgr.FlapWindow = getFlapPeriod(config.FlapWindow, DEFAULT_FLAP_WINDOW)
+	fMgr.FlapThreshold = getFlapThreshold(config.FlapThreshold)
+	fMgr.FlapQuietPeriod = getFlapPeriod(config.FlapQuietPeriod, DEFAULT_FLAP_QUIET_PERIOD)
+	fMgr.HistoryRetention = time.Duration(config.HistoryRetention) * time.Second
+	fMgr.HistoryExportDir = config.HistoryExportDir
+	fMgr.EventOverflowPolicy = strings.ToLower(config.EventOverflowPolicy)
+	fMgr.CfgRWMutex.Unlock()
+	return true, nil
+}
+
+func (fMgr *FaultManager) UpdateFMgrGlobal(oldCfg, newCfg *objects.FMgrGlobal, attrset []bool) (bool, error) {
+	err := validateHoldTime(newCfg.FaultToAlarmTransitionTime, newCfg.AlarmTransitionTime)
+	if err != nil {
+		return false, err
+	}
+	err = validateFlapConfig(newCfg.FlapWindow, newCfg.FlapThreshold, newCfg.FlapQuietPeriod)
+	if err != nil {
+		return false, err
+	}
+	err = validateHistoryConfig(newCfg.FaultHistorySize, newCfg.AlarmHistorySize, newCfg.HistoryRetention)
+	if err != nil {
+		return false, err
+	}
+	err = validateHistoryExportDir(newCfg.HistoryExportDir)
+	if err != nil {
+		return false, err
+	}
+	err = validateOverflowPolicy(newCfg.EventOverflowPolicy)
+	if err != nil {
+		return false, err
+	}
+	if len(attrset) > FMGR_GLOBAL_ALARM_HISTORY_SIZE_ATTR &&
+		(attrset[FMGR_GLOBAL_FAULT_HISTORY_SIZE_ATTR] || attrset[FMGR_GLOBAL_ALARM_HISTORY_SIZE_ATTR]) {
+		err = fMgr.setHistoryCapacity(getHistorySize(newCfg.FaultHistorySize, FAULT_RB_CAPACITY), getHistorySize(newCfg.AlarmHistorySize, ALARM_RB_CAPACITY))
+		if err != nil {
+			return false, err
+		}
+	}
+	fMgr.CfgRWMutex.Lock()
+	defer fMgr.CfgRWMutex.Unlock()
+	for idx, val := range attrset {
+		if true == val {
+			switch idx {
+			case FMGR_GLOBAL_VRF_ATTR:
+				//ObjKey Vrf
+			case FMGR_GLOBAL_FAULT_TO_ALARM_TRANSITION_TIME_ATTR:
+				fMgr.FaultToAlarmTransitionTime = getGlobalHoldTime(newCfg.FaultToAlarmTransitionTime, DEFAULT_FAULT_TO_ALARM_TRANSITION_TIME)
+			case FMGR_GLOBAL_ALARM_TRANSITION_TIME_ATTR:
+				fMgr.AlarmTransitionTime = getGlobalHoldTime(newCfg.AlarmTransitionTime, DEFAULT_ALARM_TRANSITION_TIME)
+			case FMGR_GLOBAL_FLAP_WINDOW_ATTR:
+				fMgr.FlapWindow = getFlapPeriod(newCfg.FlapWindow, DEFAULT_FLAP_WINDOW)
+			case FMGR_GLOBAL_FLAP_THRESHOLD_ATTR:
+				fMgr.FlapThreshold = getFlapThreshold(newCfg.FlapThreshold)
+			case FMGR_GLOBAL_FLAP_QUIET_PERIOD_ATTR:
+				fMgr.FlapQuietPeriod = getFlapPeriod(newCfg.FlapQuietPeriod, DEFAULT_FLAP_QUIET_PERIOD)
+			case FMGR_GLOBAL_FAULT_HISTORY_SIZE_ATTR, FMGR_GLOBAL_ALARM_HISTORY_SIZE_ATTR:
+				//Applied above
+			case FMGR_GLOBAL_HISTORY_RETENTION_ATTR:
+				fMgr.HistoryRetention = time.Duration(newCfg.HistoryRetention) * time.Second
+			case FMGR_GLOBAL_HISTORY_EXPORT_DIR_ATTR:
+				fMgr.HistoryExportDir = newCfg.HistoryExportDir
+			case FMGR_GLOBAL_EVENT_OVERFLOW_POLICY_ATTR:
+				fMgr.EventOverflowPolicy = strings.ToLower(newCfg.EventOverflowPolicy)
+			}
+		}
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) getFaultEventKey(ownerName, eventName string) (EventKey, error) {
+	evtKeyStr := EventKeyStr{
+		OwnerName: ownerName,
+		EventName: eventName,
+	}
+	evtKey, exist := fMgr.getEventKey(evtKeyStr)
+	if !exist {
+		return evtKey, errors.New("Unable to find the corresponding event")
+	}
+	if _, exist := fMgr.getFaultEvent(evtKey); !exist {
+		return evtKey, errors.New("Unable to find the corresponding faulty event")
+	}
+	return evtKey, nil
+}
+
+func (fMgr *FaultManager) CreateEventHoldTime(config *objects.EventHoldTime) (bool, error) {
+	evtKey, err := fMgr.getFaultEventKey(config.OwnerName, config.EventName)
+	if err != nil {
+		return false, err
+	}
+	err = validateHoldTime(config.FaultToAlarmTransitionTime, config.AlarmTransitionTime)
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	defer fMgr.CfgRWMutex.Unlock()
+	if _, exist := fMgr.EventHoldTimeMap[evtKey]; exist {
+		return false, errors.New(fmt.Sprintln("Hold time already configured for", config.OwnerName, config.EventName))
+	}
+	fMgr.EventHoldTimeMap[evtKey] = HoldTime{
+		FaultToAlarmTransitionTime: time.Duration(config.FaultToAlarmTransitionTime) * time.Second,
+		AlarmTransitionTime:        time.Duration(config.AlarmTransitionTime) * time.Second,
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) UpdateEventHoldTime(oldCfg, newCfg *objects.EventHoldTime, attrset []bool) (bool, error) {
+	evtKey, err := fMgr.getFaultEventKey(newCfg.OwnerName, newCfg.EventName)
+	if err != nil {
+		return false, err
+	}
+	err = validateHoldTime(newCfg.FaultToAlarmTransitionTime, newCfg.AlarmTransitionTime)
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	defer fMgr.CfgRWMutex.Unlock()
+	holdTime, exist := fMgr.EventHoldTimeMap[evtKey]
+	if !exist {
+		return false, errors.New(fmt.Sprintln("Hold time not configured for", newCfg.OwnerName, newCfg.EventName))
+	}
+	for idx, val := range attrset {
+		if true == val {
+			switch idx {
+			case 0, 1:
+				//ObjKey OwnerName, EventName
+			case 2:
+				holdTime.FaultToAlarmTransitionTime = time.Duration(newCfg.FaultToAlarmTransitionTime) * time.Second
+			case 3:
+				holdTime.AlarmTransitionTime = time.Duration(newCfg.AlarmTransitionTime) * time.Second
+			}
+		}
+	}
+	fMgr.EventHoldTimeMap[evtKey] = holdTime
+	return true, nil
+}
+
+func (fMgr *FaultManager) DeleteEventHoldTime(config *objects.EventHoldTime) (bool, error) {
+	evtKey, err := fMgr.getFaultEventKey(config.OwnerName, config.EventName)
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	defer fMgr.CfgRWMutex.Unlock()
+	if _, exist := fMgr.EventHoldTimeMap[evtKey]; !exist {
+		return false, errors.New(fmt.Sprintln("Hold time not configured for", config.OwnerName, config.EventName))
+	}
+	delete(fMgr.EventHoldTimeMap, evtKey)
+	return true, nil
+}
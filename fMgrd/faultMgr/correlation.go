@@ -0,0 +1,249 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	CORRELATION_RULES_FILE = "/opt/flexswitch/correlationRules.json"
+)
+
+type AttrMatch struct {
+	ParentAttr string
+	ChildAttr  string
+}
+
+type correlationRuleJson struct {
+	ParentOwnerName string
+	ParentEventName string
+	ChildOwnerName  string
+	ChildEventName  string
+	MatchAttrs      []AttrMatch
+}
+
+type correlationJson struct {
+	CorrelationRules []correlationRuleJson
+}
+
+// CorrelationRule suppresses alarms of the child event while an alarm of the
+// parent event is active on a source object with matching attributes. A rule
+// without any MatchAttrs matches any source object.
+type CorrelationRule struct {
+	Parent     EventKey
+	Child      EventKey
+	MatchAttrs []AttrMatch
+}
+
+// getSrcObjAttrs flattens the source object key of an event into attribute
+// name and value pairs used for matching correlation rules.
+func getSrcObjAttrs(srcObjKey interface{}) map[string]string {
+	attrs := make(map[string]string)
+	bytes, err := json.Marshal(srcObjKey)
+	if err != nil {
+		return attrs
+	}
+	var attrMap map[string]interface{}
+	if err = json.Unmarshal(bytes, &attrMap); err != nil {
+		return attrs
+	}
+	for attr, val := range attrMap {
+		attrs[attr] = fmt.Sprint(val)
+	}
+	return attrs
+}
+
+func (fMgr *FaultManager) getCorrelationEventKey(ownerName, eventName string) (EventKey, error) {
+	evtKey, err := fMgr.getFaultEventKey(ownerName, eventName)
+	if err != nil {
+		return evtKey, errors.New(fmt.Sprintln(err, ownerName, eventName))
+	}
+	return evtKey, nil
+}
+
+// loadCorrelationRules builds the rule maps out of the correlation rules file
+// and swaps them in, readers never see a partially loaded set of rules.
+func (fMgr *FaultManager) loadCorrelationRules(fileName string) error {
+	parentRuleMap := make(map[EventKey][]CorrelationRule)
+	childRuleMap := make(map[EventKey][]CorrelationRule)
+	err := fMgr.readCorrelationRules(fileName, parentRuleMap, childRuleMap)
+	fMgr.CorrRuleRWMutex.Lock()
+	fMgr.ParentRuleMap = parentRuleMap
+	fMgr.ChildRuleMap = childRuleMap
+	fMgr.CorrRuleRWMutex.Unlock()
+	return err
+}
+
+func (fMgr *FaultManager) readCorrelationRules(fileName string, parentRuleMap, childRuleMap map[EventKey][]CorrelationRule) error {
+	bytes, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fMgr.logger.Info(fmt.Sprintln("No correlation rules file found:", fileName))
+			return nil
+		}
+		return err
+	}
+	var ruleJson correlationJson
+	err = json.Unmarshal(bytes, &ruleJson)
+	if err != nil {
+		return err
+	}
+	for _, rule := range ruleJson.CorrelationRules {
+		pEvtKey, err := fMgr.getCorrelationEventKey(rule.ParentOwnerName, rule.ParentEventName)
+		if err != nil {
+			fMgr.logger.Err(fmt.Sprintln("Skipping correlation rule, invalid parent event:", err))
+			continue
+		}
+		cEvtKey, err := fMgr.getCorrelationEventKey(rule.ChildOwnerName, rule.ChildEventName)
+		if err != nil {
+			fMgr.logger.Err(fmt.Sprintln("Skipping correlation rule, invalid child event:", err))
+			continue
+		}
+		if pEvtKey == cEvtKey {
+			fMgr.logger.Err(fmt.Sprintln("Skipping correlation rule, event can not be its own parent:", rule.ParentOwnerName, rule.ParentEventName))
+			continue
+		}
+		cRule := CorrelationRule{
+			Parent:     pEvtKey,
+			Child:      cEvtKey,
+			MatchAttrs: rule.MatchAttrs,
+		}
+		parentRuleMap[cEvtKey] = append(parentRuleMap[cEvtKey], cRule)
+		childRuleMap[pEvtKey] = append(childRuleMap[pEvtKey], cRule)
+	}
+	fMgr.logger.Info(fmt.Sprintln("Loaded", len(ruleJson.CorrelationRules), "correlation rules from", fileName))
+	return nil
+}
+
+func (rule CorrelationRule) match(parentAttrs, childAttrs map[string]string) bool {
+	for _, attrMatch := range rule.MatchAttrs {
+		pVal, exist := parentAttrs[attrMatch.ParentAttr]
+		if !exist {
+			return false
+		}
+		cVal, exist := childAttrs[attrMatch.ChildAttr]
+		if !exist || cVal != pVal {
+			return false
+		}
+	}
+	return true
+}
+
+func (fMgr *FaultManager) getParentRules(cEvtKey EventKey) []CorrelationRule {
+	fMgr.CorrRuleRWMutex.RLock()
+	defer fMgr.CorrRuleRWMutex.RUnlock()
+	return fMgr.ParentRuleMap[cEvtKey]
+}
+
+func (fMgr *FaultManager) getChildRules(pEvtKey EventKey) []CorrelationRule {
+	fMgr.CorrRuleRWMutex.RLock()
+	defer fMgr.CorrRuleRWMutex.RUnlock()
+	return fMgr.ChildRuleMap[pEvtKey]
+}
+
+// findParentAlarm returns an active alarm suppressing the alarms of the child
+// event raised on the source object with the given attributes. Caller is
+// expected to hold AMapRWMutex.
+func (fMgr *FaultManager) findParentAlarm(cEvtKey EventKey, childAttrs map[string]string) (AlarmRBEntry, bool) {
+	for _, rule := range fMgr.getParentRules(cEvtKey) {
+		for _, aDataEnt := range fMgr.AlarmMap[rule.Parent] {
+			fMgr.ARBRWMutex.RLock()
+			aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+			fMgr.ARBRWMutex.RUnlock()
+			alarm := aIntf.(AlarmRBEntry)
+			if alarm.AlarmSeqNumber != aDataEnt.AlarmSeqNumber || alarm.Resolved {
+				continue
+			}
+			if rule.match(alarm.SrcObjAttrs, childAttrs) {
+				return alarm, true
+			}
+		}
+	}
+	return AlarmRBEntry{}, false
+}
+
+func (fMgr *FaultManager) getSuppressedBy(parent *AlarmRBEntry) string {
+	evtKey := EventKey{
+		DaemonId: parent.OwnerId,
+		EventId:  parent.EventId,
+	}
+	fEnt, _ := fMgr.getFaultEvent(evtKey)
+	return fmt.Sprintf("%s:%s:%s", fEnt.FaultOwnerName, fEnt.FaultEventName, parent.SrcObjKey)
+}
+
+// markSuppression marks the alarm which is about to be raised as suppressed
+// if an alarm of a parent event is active. Caller is expected to hold
+// AMapRWMutex.
+func (fMgr *FaultManager) markSuppression(evtKey EventKey, alarm *AlarmRBEntry) {
+	parent, exist := fMgr.findParentAlarm(evtKey, alarm.SrcObjAttrs)
+	if !exist {
+		return
+	}
+	alarm.Suppressed = true
+	alarm.SuppressedBy = fMgr.getSuppressedBy(&parent)
+	alarm.SuppressedBySeq = parent.AlarmSeqNumber
+	fMgr.logger.Info(fmt.Sprintln("Alarm", evtKey, alarm.SrcObjKey, "is suppressed by", alarm.SuppressedBy))
+}
+
+// reevaluateSuppressedAlarms is called once the parent alarm is cleared. The
+// child alarms suppressed by it are either moved to another active parent or
+// published as independent alarms. Caller is expected to hold AMapRWMutex.
+func (fMgr *FaultManager) reevaluateSuppressedAlarms(pEvtKey EventKey, parent *AlarmRBEntry) {
+	var alarmList []AlarmRBEntry
+	for _, rule := range fMgr.getChildRules(pEvtKey) {
+		for _, aDataEnt := range fMgr.AlarmMap[rule.Child] {
+			fMgr.ARBRWMutex.RLock()
+			aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+			fMgr.ARBRWMutex.RUnlock()
+			alarm := aIntf.(AlarmRBEntry)
+			if alarm.AlarmSeqNumber != aDataEnt.AlarmSeqNumber || alarm.Resolved ||
+				!alarm.Suppressed || alarm.SuppressedBySeq != parent.AlarmSeqNumber {
+				continue
+			}
+			newParent, exist := fMgr.findParentAlarm(rule.Child, alarm.SrcObjAttrs)
+			if exist {
+				alarm.SuppressedBy = fMgr.getSuppressedBy(&newParent)
+				alarm.SuppressedBySeq = newParent.AlarmSeqNumber
+			} else {
+				alarm.Suppressed = false
+				alarm.SuppressedBy = ""
+				alarm.SuppressedBySeq = 0
+				alarmList = append(alarmList, alarm)
+			}
+			fMgr.ARBRWMutex.Lock()
+			fMgr.AlarmRB.UpdateEntryInRingBuffer(alarm, aDataEnt.AlarmListIdx)
+			fMgr.History.RecordAlarm(alarm)
+			fMgr.ARBRWMutex.Unlock()
+			fMgr.updateAlarmSummary(&alarm, 1)
+		}
+	}
+	for _, alarm := range alarmList {
+		fMgr.publishAlarmEntry(alarm, TRANSITION_RAISED)
+	}
+}
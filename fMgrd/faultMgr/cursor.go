@@ -0,0 +1,183 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"infra/fMgrd/objects"
+	"sort"
+	"strings"
+	"utils/ringBuffer"
+)
+
+// getCursorStart returns the position to start walking the entries, ordered
+// by increasing sequence number, from and the step to walk them with.
+func getCursorStart(cursor *objects.StateCursor, length int, seqAt func(int) uint64) (int, int, error) {
+	if cursor == nil {
+		return 0, 0, errors.New("Cursor is not provided")
+	}
+	if cursor.Count <= 0 {
+		return 0, 0, errors.New("Invalid Count provided, it should be greater than 0")
+	}
+	switch strings.ToLower(cursor.Direction) {
+	case "", objects.CURSOR_BACKWARD:
+		if cursor.SeqNumber < 0 {
+			return length - 1, -1, nil
+		}
+		idx := sort.Search(length, func(i int) bool {
+			return seqAt(i) >= uint64(cursor.SeqNumber)
+		})
+		return idx - 1, -1, nil
+	case objects.CURSOR_FORWARD:
+		if cursor.SeqNumber < 0 {
+			return 0, 1, nil
+		}
+		idx := sort.Search(length, func(i int) bool {
+			return seqAt(i) > uint64(cursor.SeqNumber)
+		})
+		return idx, 1, nil
+	}
+	return 0, 0, errors.New("Invalid Direction provided, it should be backward or forward")
+}
+
+// rbView walks the entries of a ring buffer, ordered by increasing sequence
+// number, in place. Caller is expected to hold the ring buffer lock.
+type rbView struct {
+	rb     *ringBuffer.RingBuffer
+	length int
+	start  int // Index of the oldest entry
+}
+
+// newRBView locates the oldest entry, the sequence numbers drop past the
+// newest entry once the ring buffer wraps.
+func newRBView(rb *ringBuffer.RingBuffer, length int, seqOf func(interface{}) uint64) rbView {
+	view := rbView{
+		rb:     rb,
+		length: length,
+	}
+	if length > 0 {
+		firstSeq := seqOf(rb.GetEntryFromRingBuffer(0))
+		view.start = sort.Search(length, func(i int) bool {
+			return seqOf(rb.GetEntryFromRingBuffer(i)) < firstSeq
+		}) % length
+	}
+	return view
+}
+
+func (view rbView) entryAt(pos int) interface{} {
+	return view.rb.GetEntryFromRingBuffer((view.start + pos) % view.length)
+}
+
+func faultSeqOf(fIntf interface{}) uint64 {
+	return fIntf.(FaultRBEntry).FaultSeqNumber
+}
+
+func alarmSeqOf(aIntf interface{}) uint64 {
+	return aIntf.(AlarmRBEntry).AlarmSeqNumber
+}
+
+// GetFaultStateByCursor copies out of the fault ring buffer only the
+// entries returned, so that walking the history chunk by chunk stays linear.
+func (fMgr *FaultManager) GetFaultStateByCursor(cursor *objects.StateCursor, filter *objects.StateFilter) (*objects.FaultStateCursorInfo, error) {
+	sFilter, err := compileStateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	retObj := &objects.FaultStateCursorInfo{}
+	var faults []FaultRBEntry
+	fMgr.FRBRWMutex.RLock()
+	view := newRBView(fMgr.FaultRB, fMgr.FaultRBCount, faultSeqOf)
+	start, step, err := getCursorStart(cursor, view.length, func(i int) uint64 {
+		return faultSeqOf(view.entryAt(i))
+	})
+	for idx := start; err == nil && idx >= 0 && idx < view.length; idx += step {
+		fault := view.entryAt(idx).(FaultRBEntry)
+		if !fMgr.matchFault(sFilter, &fault) {
+			continue
+		}
+		if len(faults) == cursor.Count {
+			retObj.More = true
+			break
+		}
+		faults = append(faults, fault)
+	}
+	fMgr.FRBRWMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	retObj.NextSeqNumber = cursor.SeqNumber
+	for idx := range faults {
+		retObj.NextSeqNumber = int64(faults[idx].FaultSeqNumber)
+		fObj, err := fMgr.GetFaultStateObject(&faults[idx])
+		if err != nil {
+			continue
+		}
+		retObj.List = append(retObj.List, fObj)
+		retObj.Count++
+	}
+	return retObj, nil
+}
+
+// GetAlarmStateByCursor is the alarm counterpart of GetFaultStateByCursor.
+func (fMgr *FaultManager) GetAlarmStateByCursor(cursor *objects.StateCursor, filter *objects.StateFilter) (*objects.AlarmStateCursorInfo, error) {
+	sFilter, err := compileStateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	retObj := &objects.AlarmStateCursorInfo{}
+	var alarms []AlarmRBEntry
+	fMgr.ARBRWMutex.RLock()
+	view := newRBView(fMgr.AlarmRB, fMgr.AlarmRBCount, alarmSeqOf)
+	start, step, err := getCursorStart(cursor, view.length, func(i int) uint64 {
+		return alarmSeqOf(view.entryAt(i))
+	})
+	for idx := start; err == nil && idx >= 0 && idx < view.length; idx += step {
+		alarm := view.entryAt(idx).(AlarmRBEntry)
+		if !fMgr.matchAlarm(sFilter, &alarm) {
+			continue
+		}
+		if len(alarms) == cursor.Count {
+			retObj.More = true
+			break
+		}
+		alarms = append(alarms, alarm)
+	}
+	fMgr.ARBRWMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	retObj.NextSeqNumber = cursor.SeqNumber
+	for idx := range alarms {
+		retObj.NextSeqNumber = int64(alarms[idx].AlarmSeqNumber)
+		aObj, err := fMgr.GetAlarmStateObject(&alarms[idx])
+		if err != nil {
+			continue
+		}
+		retObj.List = append(retObj.List, aObj)
+		retObj.Count++
+	}
+	return retObj, nil
+}
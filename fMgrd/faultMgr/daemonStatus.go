@@ -0,0 +1,187 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"infra/sysd/sysdCommonDefs"
+	"strings"
+	"utils/eventUtils"
+)
+
+// The DaemonDown fault and the DaemonUp clearing event are defined by fault
+// manager itself for SYSD, their source object is keyed by the daemon Name.
+const (
+	DAEMON_STATUS_OWNER  = "SYSD"
+	DAEMON_DOWN_EVENT    = "DaemonDown"
+	DAEMON_UP_EVENT      = "DaemonUp"
+	DAEMON_SRC_OBJ       = "Daemon"
+	DAEMON_DOWN_SEVERITY = "Major"
+)
+
+// SYSD gets a reserved DaemonId when events.json does not define it, so that
+// the history of its events is restored under the same owner whatever the
+// DaemonIds in events.json are.
+const DAEMON_STATUS_OWNER_ID = ^eventUtils.OwnerId(0)
+
+type objKeyIntf interface {
+	GetObjDBKey([]byte) (string, string, error)
+}
+
+type daemonObjKey struct {
+	Name string
+}
+
+func (key daemonObjKey) GetObjDBKey(data []byte) (string, string, error) {
+	var obj daemonObjKey
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return "", "", err
+	}
+	if obj.Name == "" {
+		return "", "", errors.New("Daemon Name is not provided")
+	}
+	return "Name:" + obj.Name, "Daemon#" + obj.Name, nil
+}
+
+// internalEventKeyMap holds the source objects of the events defined by
+// fault manager, they have no entry in models/events nor in the config DB.
+var internalEventKeyMap = map[string]map[string]objKeyIntf{
+	DAEMON_STATUS_OWNER: {
+		DAEMON_SRC_OBJ: daemonObjKey{},
+	},
+}
+
+func isInternalSrcObj(ownerName, srcObjName string) bool {
+	_, exist := internalEventKeyMap[strings.ToUpper(ownerName)][srcObjName]
+	return exist
+}
+
+// getInternalObjUUID derives a stable UUID out of the object DB key, for the
+// source objects which are not in the config DB.
+func getInternalObjUUID(dbObjKey string) string {
+	sum := md5.Sum([]byte(dbObjKey))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// withDaemonStatusEvents returns the event definitions along with the
+// DaemonDown and DaemonUp events of SYSD, unless events.json defines them.
+// The given definitions are not modified.
+func withDaemonStatusEvents(evtJson *eventUtils.EventJson) *eventUtils.EventJson {
+	newEvtJson := &eventUtils.EventJson{
+		DaemonEvents: make([]eventUtils.DaemonEvent, len(evtJson.DaemonEvents)),
+	}
+	copy(newEvtJson.DaemonEvents, evtJson.DaemonEvents)
+	sysdIdx := -1
+	for idx, daemon := range newEvtJson.DaemonEvents {
+		if daemon.DaemonName == DAEMON_STATUS_OWNER {
+			sysdIdx = idx
+		}
+	}
+	if sysdIdx == -1 {
+		newEvtJson.DaemonEvents = append(newEvtJson.DaemonEvents, eventUtils.DaemonEvent{
+			DaemonId:   DAEMON_STATUS_OWNER_ID,
+			DaemonName: DAEMON_STATUS_OWNER,
+		})
+		sysdIdx = len(newEvtJson.DaemonEvents) - 1
+	}
+	sysd := &newEvtJson.DaemonEvents[sysdIdx]
+	var maxEventId eventUtils.EventId
+	for _, evt := range sysd.EventList {
+		if evt.EventName == DAEMON_DOWN_EVENT || evt.EventName == DAEMON_UP_EVENT {
+			return evtJson
+		}
+		if evt.EventId > maxEventId {
+			maxEventId = evt.EventId
+		}
+	}
+	eventList := make([]eventUtils.EventStruct, len(sysd.EventList), len(sysd.EventList)+2)
+	copy(eventList, sysd.EventList)
+	sysd.EventList = append(eventList,
+		eventUtils.EventStruct{
+			EventId:     maxEventId + 1,
+			EventName:   DAEMON_DOWN_EVENT,
+			Description: "Daemon is down",
+			SrcObjName:  DAEMON_SRC_OBJ,
+			IsFault:     true,
+			Fault: eventUtils.FaultDetail{
+				RaiseFault:       true,
+				ClearingEventId:  int(maxEventId + 2),
+				ClearingDaemonId: int(sysd.DaemonId),
+				AlarmSeverity:    DAEMON_DOWN_SEVERITY,
+			},
+		},
+		eventUtils.EventStruct{
+			EventId:     maxEventId + 2,
+			EventName:   DAEMON_UP_EVENT,
+			Description: "Daemon is up",
+			SrcObjName:  DAEMON_SRC_OBJ,
+		})
+	return newEvtJson
+}
+
+// ProcessDaemonStatus turns the keepalive status of a daemon published by
+// sysd into DaemonDown fault when the daemon is restarting or stopped, and
+// clears the fault once the daemon is up again.
+func (fMgr *FaultManager) ProcessDaemonStatus(status sysdCommonDefs.DaemonStatus) error {
+	var eventName string
+	switch status.Status {
+	case sysdCommonDefs.RESTARTING, sysdCommonDefs.STOPPED:
+		eventName = DAEMON_DOWN_EVENT
+	case sysdCommonDefs.UP:
+		eventName = DAEMON_UP_EVENT
+	default:
+		return nil
+	}
+	evtKeyStr := EventKeyStr{
+		OwnerName: DAEMON_STATUS_OWNER,
+		EventName: eventName,
+	}
+	evtKey, exist := fMgr.getEventKey(evtKeyStr)
+	if !exist {
+		return errors.New(fmt.Sprintln("Unable to find the event", DAEMON_STATUS_OWNER, eventName))
+	}
+	srcObjName, err := fMgr.getEventSrcObjName(evtKey)
+	if err != nil {
+		return err
+	}
+
+	evt := FaultEvent{
+		Event: eventUtils.Event{
+			OwnerId:     eventUtils.OwnerId(evtKey.DaemonId),
+			OwnerName:   DAEMON_STATUS_OWNER,
+			EvtId:       eventUtils.EventId(evtKey.EventId),
+			EventName:   eventName,
+			TimeStamp:   fMgr.Clock.Now(),
+			Description: fmt.Sprintf("Daemon %s is %s", status.Name, sysdCommonDefs.ConvertDaemonStateCodeToString(status.Status)),
+			SrcObjName:  srcObjName,
+			SrcObjKey:   map[string]interface{}{"Name": status.Name},
+		},
+	}
+	fMgr.logger.Info(fmt.Sprintln("Daemon status received:", status.Name, sysdCommonDefs.ConvertDaemonStateCodeToString(status.Status)))
+	return fMgr.queueEvent(evt)
+}
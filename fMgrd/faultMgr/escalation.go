@@ -0,0 +1,252 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"strings"
+	"time"
+)
+
+// Alarm severities in increasing order of escalation
+var severityLevels = []string{"Warning", "Minor", "Major", "Critical"}
+
+type EscalationPolicy struct {
+	EscalationInterval time.Duration
+	MaxSeverity        string
+}
+
+func getSeverityLevel(severity string) int {
+	for idx, sev := range severityLevels {
+		if strings.ToLower(sev) == strings.ToLower(severity) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// getNextSeverity returns the severity the alarm has to be escalated to or
+// "" when no further escalation is allowed by the policy.
+func getNextSeverity(severity string, policy EscalationPolicy) string {
+	level := getSeverityLevel(severity)
+	if level == -1 || level >= getSeverityLevel(policy.MaxSeverity) {
+		return ""
+	}
+	return severityLevels[level+1]
+}
+
+func getAlarmSeverity(alarm *AlarmRBEntry, fEnt FaultDetail) string {
+	if alarm.Severity != "" {
+		return alarm.Severity
+	}
+	return fEnt.AlarmSeverity
+}
+
+func validateEscalationPolicy(interval int32, maxSeverity string) error {
+	if interval <= 0 {
+		return errors.New("Invalid EscalationInterval value provided")
+	}
+	if getSeverityLevel(maxSeverity) == -1 {
+		return errors.New(fmt.Sprintln("Invalid MaxSeverity value provided, supported values are:", severityLevels))
+	}
+	return nil
+}
+
+func (fMgr *FaultManager) getEscalationPolicy(evtKey EventKey) (EscalationPolicy, bool) {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	policy, exist := fMgr.EscalationPolicyMap[evtKey]
+	return policy, exist
+}
+
+// StartEscalationTimer arms the timer for the next escalation step of the
+// alarm, nil is returned if the alarm is not to be escalated any further.
+// Caller is expected to hold AMapRWMutex.
+func (fMgr *FaultManager) StartEscalationTimer(evtKey EventKey, fObjKey FaultObjKey, alarm *AlarmRBEntry) Timer {
+	policy, exist := fMgr.getEscalationPolicy(evtKey)
+	if !exist || alarm.Resolved {
+		return nil
+	}
+	fEnt, _ := fMgr.getFaultEvent(evtKey)
+	if getNextSeverity(getAlarmSeverity(alarm, fEnt), policy) == "" {
+		return nil
+	}
+	lastTime := alarm.OccuranceTime
+	if !alarm.EscalationTime.IsZero() {
+		lastTime = alarm.EscalationTime
+	}
+	delay := policy.EscalationInterval - fMgr.Clock.Now().Sub(lastTime)
+	if delay < 0 {
+		delay = 0
+	}
+	return fMgr.Clock.AfterFunc(delay, func() {
+		fMgr.escalateAlarm(evtKey, fObjKey)
+	})
+}
+
+func (fMgr *FaultManager) escalateAlarm(evtKey EventKey, fObjKey FaultObjKey) {
+	policy, exist := fMgr.getEscalationPolicy(evtKey)
+	if !exist {
+		return
+	}
+	fMgr.AMapRWMutex.Lock()
+	defer fMgr.AMapRWMutex.Unlock()
+	aDataEnt, exist := fMgr.AlarmMap[evtKey][fObjKey]
+	if !exist {
+		fMgr.logger.Debug("Alarm Data entry doesnot exist, hence skipping escalation")
+		return
+	}
+	fEnt, _ := fMgr.getFaultEvent(evtKey)
+	fMgr.ARBRWMutex.Lock()
+	aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+	aRBData := aIntf.(AlarmRBEntry)
+	if aRBData.AlarmSeqNumber != aDataEnt.AlarmSeqNumber || aRBData.Resolved {
+		fMgr.ARBRWMutex.Unlock()
+		return
+	}
+	severity := getNextSeverity(getAlarmSeverity(&aRBData, fEnt), policy)
+	if severity == "" {
+		fMgr.ARBRWMutex.Unlock()
+		return
+	}
+	fMgr.logger.Info(fmt.Sprintln("Escalating alarm", evtKey, fObjKey, "to", severity))
+	if !aRBData.Suppressed {
+		fMgr.changeAlarmSummarySeverity(getAlarmSeverity(&aRBData, fEnt), severity)
+	}
+	aRBData.Severity = severity
+	aRBData.EscalationLevel++
+	aRBData.EscalationTime = fMgr.Clock.Now()
+	fMgr.AlarmRB.UpdateEntryInRingBuffer(aRBData, aDataEnt.AlarmListIdx)
+	fMgr.History.RecordAlarm(aRBData)
+	fMgr.ARBRWMutex.Unlock()
+	aDataEnt.EscalationTimer = fMgr.StartEscalationTimer(evtKey, fObjKey, &aRBData)
+	fMgr.AlarmMap[evtKey][fObjKey] = aDataEnt
+	fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_NONE)
+}
+
+// stopEscalation stops any further escalation of the alarm being resolved.
+// The resolved alarm keeps the severity and level it was escalated to.
+func (fMgr *FaultManager) stopEscalation(aDataEnt *AlarmData) {
+	if aDataEnt.EscalationTimer != nil {
+		aDataEnt.EscalationTimer.Stop()
+		aDataEnt.EscalationTimer = nil
+	}
+}
+
+// rearmEscalationTimers restarts the escalation timers of all the active
+// alarms of the event as per the current policy.
+func (fMgr *FaultManager) rearmEscalationTimers(evtKey EventKey) {
+	fMgr.AMapRWMutex.Lock()
+	defer fMgr.AMapRWMutex.Unlock()
+	aDataMapEnt, _ := fMgr.AlarmMap[evtKey]
+	for fObjKey, aDataEnt := range aDataMapEnt {
+		if aDataEnt.EscalationTimer != nil {
+			aDataEnt.EscalationTimer.Stop()
+		}
+		fMgr.ARBRWMutex.RLock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		fMgr.ARBRWMutex.RUnlock()
+		alarm := aIntf.(AlarmRBEntry)
+		aDataEnt.EscalationTimer = nil
+		if alarm.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+			aDataEnt.EscalationTimer = fMgr.StartEscalationTimer(evtKey, fObjKey, &alarm)
+		}
+		aDataMapEnt[fObjKey] = aDataEnt
+	}
+}
+
+func (fMgr *FaultManager) CreateAlarmEscalation(config *objects.AlarmEscalation) (bool, error) {
+	evtKey, err := fMgr.getFaultEventKey(config.OwnerName, config.EventName)
+	if err != nil {
+		return false, err
+	}
+	err = validateEscalationPolicy(config.EscalationInterval, config.MaxSeverity)
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	if _, exist := fMgr.EscalationPolicyMap[evtKey]; exist {
+		fMgr.CfgRWMutex.Unlock()
+		return false, errors.New(fmt.Sprintln("Escalation policy already configured for", config.OwnerName, config.EventName))
+	}
+	fMgr.EscalationPolicyMap[evtKey] = EscalationPolicy{
+		EscalationInterval: time.Duration(config.EscalationInterval) * time.Second,
+		MaxSeverity:        config.MaxSeverity,
+	}
+	fMgr.CfgRWMutex.Unlock()
+	fMgr.rearmEscalationTimers(evtKey)
+	return true, nil
+}
+
+func (fMgr *FaultManager) UpdateAlarmEscalation(oldCfg, newCfg *objects.AlarmEscalation, attrset []bool) (bool, error) {
+	evtKey, err := fMgr.getFaultEventKey(newCfg.OwnerName, newCfg.EventName)
+	if err != nil {
+		return false, err
+	}
+	err = validateEscalationPolicy(newCfg.EscalationInterval, newCfg.MaxSeverity)
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	policy, exist := fMgr.EscalationPolicyMap[evtKey]
+	if !exist {
+		fMgr.CfgRWMutex.Unlock()
+		return false, errors.New(fmt.Sprintln("Escalation policy not configured for", newCfg.OwnerName, newCfg.EventName))
+	}
+	for idx, val := range attrset {
+		if true == val {
+			switch idx {
+			case 0, 1:
+				//ObjKey OwnerName, EventName
+			case 2:
+				policy.EscalationInterval = time.Duration(newCfg.EscalationInterval) * time.Second
+			case 3:
+				policy.MaxSeverity = newCfg.MaxSeverity
+			}
+		}
+	}
+	fMgr.EscalationPolicyMap[evtKey] = policy
+	fMgr.CfgRWMutex.Unlock()
+	fMgr.rearmEscalationTimers(evtKey)
+	return true, nil
+}
+
+func (fMgr *FaultManager) DeleteAlarmEscalation(config *objects.AlarmEscalation) (bool, error) {
+	evtKey, err := fMgr.getFaultEventKey(config.OwnerName, config.EventName)
+	if err != nil {
+		return false, err
+	}
+	fMgr.CfgRWMutex.Lock()
+	if _, exist := fMgr.EscalationPolicyMap[evtKey]; !exist {
+		fMgr.CfgRWMutex.Unlock()
+		return false, errors.New(fmt.Sprintln("Escalation policy not configured for", config.OwnerName, config.EventName))
+	}
+	delete(fMgr.EscalationPolicyMap, evtKey)
+	fMgr.CfgRWMutex.Unlock()
+	// Already escalated alarms keep their severity till they are cleared
+	fMgr.rearmEscalationTimers(evtKey)
+	return true, nil
+}
@@ -0,0 +1,84 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"infra/fMgrd/objects"
+)
+
+// StateTransition tells whether a published fault or alarm state is a
+// change of the state or only a refresh of it (ack, escalation, republish...)
+type StateTransition int
+
+const (
+	TRANSITION_NONE StateTransition = iota
+	TRANSITION_RAISED
+	TRANSITION_CLEARED
+)
+
+// AlarmExporter forwards alarm state changes to an external management
+// system in addition to the alarm publication over redis.
+type AlarmExporter interface {
+	ExportAlarm(aObj objects.AlarmState)
+}
+
+// FaultExporter forwards fault state changes to an external management
+// system in addition to the fault publication over redis.
+type FaultExporter interface {
+	ExportFault(fObj objects.FaultState)
+}
+
+func (fMgr *FaultManager) RegisterAlarmExporter(exporter AlarmExporter) {
+	fMgr.ExporterRWMutex.Lock()
+	fMgr.AlarmExporters = append(fMgr.AlarmExporters, exporter)
+	fMgr.ExporterRWMutex.Unlock()
+}
+
+func (fMgr *FaultManager) RegisterFaultExporter(exporter FaultExporter) {
+	fMgr.ExporterRWMutex.Lock()
+	fMgr.FaultExporters = append(fMgr.FaultExporters, exporter)
+	fMgr.ExporterRWMutex.Unlock()
+}
+
+func (fMgr *FaultManager) exportAlarm(aObj objects.AlarmState, transition StateTransition) {
+	if transition == TRANSITION_NONE {
+		return
+	}
+	fMgr.ExporterRWMutex.RLock()
+	defer fMgr.ExporterRWMutex.RUnlock()
+	for _, exporter := range fMgr.AlarmExporters {
+		exporter.ExportAlarm(aObj)
+	}
+}
+
+func (fMgr *FaultManager) exportFault(fObj objects.FaultState, transition StateTransition) {
+	if transition == TRANSITION_NONE {
+		return
+	}
+	fMgr.ExporterRWMutex.RLock()
+	defer fMgr.ExporterRWMutex.RUnlock()
+	for _, exporter := range fMgr.FaultExporters {
+		exporter.ExportFault(fObj)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"infra/fMgrd/eventBus"
 	"infra/fMgrd/objects"
 	//"models/events"
 	"strings"
@@ -39,19 +40,26 @@ import (
 )
 
 type PubIntf interface {
-	Publish(string, interface{}, interface{})
+	eventBus.Publisher
+}
+
+// DBIntf is the subset of dbutils.DBIntf used by the fault manager
+type DBIntf interface {
 	Connect() error
+	GetUUIDFromObjKey(string) (string, error)
 }
 
 type FaultManager struct {
 	logger                     logging.LoggerIntf
-	dbHdl                      dbutils.DBIntf
-	EventCh                    chan []byte
+	dbHdl                      DBIntf
+	Clock                      Clock
+	EventCh                    chan EventMsg
 	PauseEventProcessCh        chan bool
 	PauseEventProcessAckCh     chan bool
-	FaultEventMap              map[EventKey]FaultDetail
-	NonFaultEventMap           map[EventKey]NonFaultDetail
-	OwnerEventNameMap          map[EventKeyStr]EventKey
+	EvtMapRWMutex              sync.RWMutex
+	FaultEventMap              map[EventKey]FaultDetail    // Protected by EvtMapRWMutex
+	NonFaultEventMap           map[EventKey]NonFaultDetail // Protected by EvtMapRWMutex
+	OwnerEventNameMap          map[EventKeyStr]EventKey    // Protected by EvtMapRWMutex
 	FMapRWMutex                sync.RWMutex
 	FaultMap                   map[EventKey]FaultDataMap
 	AMapRWMutex                sync.RWMutex
@@ -60,37 +68,110 @@ type FaultManager struct {
 	FaultRB                    *ringBuffer.RingBuffer
 	ARBRWMutex                 sync.RWMutex
 	AlarmRB                    *ringBuffer.RingBuffer
-	DaemonList                 []string
+	FaultRBCapacity            int            // Protected by FRBRWMutex
+	FaultRBCount               int            // Protected by FRBRWMutex
+	AlarmRBCapacity            int            // Protected by ARBRWMutex
+	AlarmRBCount               int            // Protected by ARBRWMutex
+	DaemonList                 []string       // Protected by EvtMapRWMutex
+	DaemonNameMap              map[int]string // Key is DaemonId, protected by EvtMapRWMutex
 	FaultSeqNumber             uint64
 	AlarmSeqNumber             uint64
+	CfgRWMutex                 sync.RWMutex
 	FaultToAlarmTransitionTime time.Duration
 	AlarmTransitionTime        time.Duration
+	EventHoldTimeMap           map[EventKey]HoldTime
+	FlapWindow                 time.Duration
+	FlapThreshold              int
+	FlapQuietPeriod            time.Duration
+	HistoryRetention           time.Duration
+	HistoryExportDir           string
+	EventOverflowPolicy        string
+	FlapMap                    map[EventKey]map[FaultObjKey]*FlapData
+	EscalationPolicyMap        map[EventKey]EscalationPolicy
+	CorrRuleRWMutex            sync.RWMutex
+	ParentRuleMap              map[EventKey][]CorrelationRule // Key is child event, protected by CorrRuleRWMutex
+	ChildRuleMap               map[EventKey][]CorrelationRule // Key is parent event, protected by CorrRuleRWMutex
+	ExporterRWMutex            sync.RWMutex
+	AlarmExporters             []AlarmExporter
+	FaultExporters             []FaultExporter
+	SnmpTrapExp                *SnmpTrapExporter
+	SyslogExp                  *SyslogExporter
+	ReloadMutex                sync.Mutex
+	ReloadState                objects.EventsReloadState
+	ExportStateMutex           sync.Mutex
+	ExportStateMap             map[string]objects.HistoryExportState // Key is FileName, protected by ExportStateMutex
+	SummaryMutex               sync.Mutex
+	Summary                    AlarmSummary
 	FaultPubHdl                PubIntf
 	AlarmPubHdl                PubIntf
+	History                    *HistoryJournal
+	ShelveRWMutex              sync.RWMutex
+	ShelveMap                  map[EventKey]ShelveDataMap
+	MaintenanceRWMutex         sync.RWMutex
+	MaintenanceMap             map[string]*MaintenanceWindow // Key is window name
+	IngestMutex                sync.Mutex
+	IngestStatsMap             map[string]*IngestStats // Key is owner name
 }
 
-func NewFaultManager(logger logging.LoggerIntf) *FaultManager {
+const (
+	FAULT_RB_CAPACITY = 100000 // Default max 100000 entries in fault database
+	ALARM_RB_CAPACITY = 100000 // Default max 100000 entries in alarm database
+)
+
+func NewFaultManager(logger logging.LoggerIntf, bus eventBus.Publisher) *FaultManager {
+	return NewFaultManagerWithHandles(logger, dbutils.NewDBUtil(logger), bus, SystemClock{})
+}
+
+// NewFaultManagerWithHandles creates the fault manager on top of the given
+// DB handle, publisher and clock, so that it can be run in simulation.
+func NewFaultManagerWithHandles(logger logging.LoggerIntf, dbHdl DBIntf, bus PubIntf, clock Clock) *FaultManager {
 	fMgr := &FaultManager{}
 	fMgr.logger = logger
-	fMgr.EventCh = make(chan []byte, 1000)
+	fMgr.Clock = clock
+	fMgr.EventCh = make(chan EventMsg, EVENT_CH_SIZE)
 	fMgr.PauseEventProcessCh = make(chan bool, 1)
 	fMgr.PauseEventProcessAckCh = make(chan bool, 1)
 	fMgr.FaultEventMap = make(map[EventKey]FaultDetail)
 	fMgr.NonFaultEventMap = make(map[EventKey]NonFaultDetail)
 	fMgr.OwnerEventNameMap = make(map[EventKeyStr]EventKey)
-	fMgr.FaultMap = make(map[EventKey]FaultDataMap) //Existing Faults
-	fMgr.AlarmMap = make(map[EventKey]AlarmDataMap) //Existing Alarm
+	fMgr.DaemonNameMap = make(map[int]string)
+	fMgr.FaultMap = make(map[EventKey]FaultDataMap)   //Existing Faults
+	fMgr.AlarmMap = make(map[EventKey]AlarmDataMap)   //Existing Alarm
+	fMgr.ShelveMap = make(map[EventKey]ShelveDataMap) //Shelved Alarms
+	fMgr.MaintenanceMap = make(map[string]*MaintenanceWindow)
+	fMgr.IngestStatsMap = make(map[string]*IngestStats)
 	fMgr.FaultRB = new(ringBuffer.RingBuffer)
-	fMgr.FaultRB.SetRingBufferCapacity(100000) // Max 100000 entries in fault database
+	fMgr.FaultRB.SetRingBufferCapacity(FAULT_RB_CAPACITY)
 	fMgr.AlarmRB = new(ringBuffer.RingBuffer)
-	fMgr.AlarmRB.SetRingBufferCapacity(100000) // Max 100000 entries in alarm database
+	fMgr.AlarmRB.SetRingBufferCapacity(ALARM_RB_CAPACITY)
+	fMgr.FaultRBCapacity = FAULT_RB_CAPACITY
+	fMgr.AlarmRBCapacity = ALARM_RB_CAPACITY
 	fMgr.FaultSeqNumber = 0
 	fMgr.AlarmSeqNumber = 0
-	fMgr.FaultToAlarmTransitionTime = time.Duration(3) * time.Second
-	fMgr.AlarmTransitionTime = time.Duration(3) * time.Second
-	fMgr.dbHdl = dbutils.NewDBUtil(logger)
-	fMgr.FaultPubHdl = dbutils.NewDBUtil(logger)
-	fMgr.AlarmPubHdl = dbutils.NewDBUtil(logger)
+	fMgr.FaultToAlarmTransitionTime = DEFAULT_FAULT_TO_ALARM_TRANSITION_TIME
+	fMgr.AlarmTransitionTime = DEFAULT_ALARM_TRANSITION_TIME
+	fMgr.EventHoldTimeMap = make(map[EventKey]HoldTime)
+	fMgr.FlapWindow = DEFAULT_FLAP_WINDOW
+	fMgr.FlapThreshold = DEFAULT_FLAP_THRESHOLD
+	fMgr.FlapQuietPeriod = DEFAULT_FLAP_QUIET_PERIOD
+	fMgr.FlapMap = make(map[EventKey]map[FaultObjKey]*FlapData)
+	fMgr.EscalationPolicyMap = make(map[EventKey]EscalationPolicy)
+	fMgr.ParentRuleMap = make(map[EventKey][]CorrelationRule)
+	fMgr.ChildRuleMap = make(map[EventKey][]CorrelationRule)
+	fMgr.dbHdl = dbHdl
+	fMgr.FaultPubHdl = bus
+	fMgr.AlarmPubHdl = bus
+	fMgr.SnmpTrapExp = NewSnmpTrapExporter(logger)
+	fMgr.RegisterAlarmExporter(fMgr.SnmpTrapExp)
+	fMgr.SyslogExp = NewSyslogExporter(logger)
+	fMgr.RegisterAlarmExporter(fMgr.SyslogExp)
+	fMgr.RegisterFaultExporter(fMgr.SyslogExp)
+	fMgr.Summary.SeverityCount = make(map[string]int)
+	fMgr.Summary.OwnerCount = make(map[string]int)
+	fMgr.ReloadState.LastReloadTime = "N/A"
+	fMgr.ReloadState.Status = "N/A"
+	fMgr.ExportStateMap = make(map[string]objects.HistoryExportState)
+	fMgr.History = NewHistoryJournal(logger, HISTORY_FILE, 2*(FAULT_RB_CAPACITY+ALARM_RB_CAPACITY))
 	return fMgr
 }
 
@@ -100,6 +181,16 @@ func (fMgr *FaultManager) InitFaultManager() error {
 		fMgr.logger.Err(fmt.Sprintln("Error Initializing Fault Manager DS:", err))
 		return err
 	}
+	err = fMgr.loadCorrelationRules(CORRELATION_RULES_FILE)
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Error Loading Correlation Rules:", err))
+	}
+	err = fMgr.restoreHistory()
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Error Restoring Fault and Alarm History:", err))
+	}
+	go fMgr.HistoryCompactor()
+	go fMgr.HistoryRetentionPurger()
 	err = fMgr.dbHdl.Connect()
 	if err != nil {
 		fMgr.logger.Err(fmt.Sprintln("Error Initializing Fault Manager DB Handler:", err))
@@ -121,21 +212,7 @@ func (fMgr *FaultManager) EventProcessor() {
 	for {
 		select {
 		case msg := <-fMgr.EventCh:
-			var evt eventUtils.Event
-			err := json.Unmarshal(msg, &evt)
-			if err != nil {
-				fMgr.logger.Err(fmt.Sprintln("Unable to Unmarshal the byte stream", err))
-				continue
-			}
-			fMgr.logger.Debug(fmt.Sprintln("OwnerId:", evt.OwnerId))
-			fMgr.logger.Debug(fmt.Sprintln("OwnerName:", evt.OwnerName))
-			fMgr.logger.Debug(fmt.Sprintln("EvtId:", evt.EvtId))
-			fMgr.logger.Debug(fmt.Sprintln("EventName:", evt.EventName))
-			fMgr.logger.Debug(fmt.Sprintln("Timestamp:", evt.TimeStamp))
-			fMgr.logger.Debug(fmt.Sprintln("Description:", evt.Description))
-			fMgr.logger.Debug(fmt.Sprintln("SrcObjName:", evt.SrcObjName))
-
-			fMgr.processEvents(evt)
+			fMgr.ProcessEvent(msg.Owner, msg.Data)
 		case _ = <-fMgr.PauseEventProcessCh:
 			fMgr.PauseEventProcessAckCh <- true
 			<-fMgr.PauseEventProcessCh
@@ -143,16 +220,76 @@ func (fMgr *FaultManager) EventProcessor() {
 	}
 }
 
+// ProcessEvent handles one event received from the owner daemon. It must
+// only be called from EventProcessor, or in place of it.
+func (fMgr *FaultManager) ProcessEvent(owner string, msg []byte) error {
+	var evt FaultEvent
+	err := json.Unmarshal(msg, &evt)
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Unable to Unmarshal the byte stream", err))
+		fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.UnmarshalErrors++ })
+		return err
+	}
+	fMgr.logger.Debug(fmt.Sprintln("OwnerId:", evt.OwnerId))
+	fMgr.logger.Debug(fmt.Sprintln("OwnerName:", evt.OwnerName))
+	fMgr.logger.Debug(fmt.Sprintln("EvtId:", evt.EvtId))
+	fMgr.logger.Debug(fmt.Sprintln("EventName:", evt.EventName))
+	fMgr.logger.Debug(fmt.Sprintln("Timestamp:", evt.TimeStamp))
+	fMgr.logger.Debug(fmt.Sprintln("Description:", evt.Description))
+	fMgr.logger.Debug(fmt.Sprintln("SrcObjName:", evt.SrcObjName))
+
+	return fMgr.countProcessedEvent(owner, fMgr.processEvents(evt))
+}
+
+type EventMaps struct {
+	FaultEventMap     map[EventKey]FaultDetail
+	NonFaultEventMap  map[EventKey]NonFaultDetail
+	OwnerEventNameMap map[EventKeyStr]EventKey
+	DaemonList        []string
+	DaemonNameMap     map[int]string
+}
+
 func (fMgr *FaultManager) initFMgrDS() error {
-	evtMap := make(map[EventKey]EvtDetail)
 	evtJson, err := eventUtils.ParseEventsJson()
 	if err != nil {
 		fMgr.logger.Err(fmt.Sprintln("Error Parsing the events.json", err))
 		return err
 	}
+	fMgr.InitEventMaps(evtJson)
+	return nil
+}
+
+// InitEventMaps loads the event definitions, problems found in them are
+// logged and returned
+func (fMgr *FaultManager) InitEventMaps(evtJson *eventUtils.EventJson) []error {
+	evtMaps, errList := LoadEventMaps(evtJson)
+	for _, err := range errList {
+		fMgr.logger.Err(fmt.Sprintln(err))
+	}
+	fMgr.EvtMapRWMutex.Lock()
+	fMgr.FaultEventMap = evtMaps.FaultEventMap
+	fMgr.NonFaultEventMap = evtMaps.NonFaultEventMap
+	fMgr.OwnerEventNameMap = evtMaps.OwnerEventNameMap
+	fMgr.DaemonNameMap = evtMaps.DaemonNameMap
+	fMgr.DaemonList = evtMaps.DaemonList
+	fMgr.EvtMapRWMutex.Unlock()
+	return errList
+}
+
+// BuildEventMaps builds the fault and non fault event maps out of parsed
+// events.json, problems found in the event definitions are returned in
+// errList and the corresponding events are skipped.
+func BuildEventMaps(evtJson *eventUtils.EventJson) (evtMaps *EventMaps, errList []error) {
+	evtMaps = &EventMaps{
+		FaultEventMap:     make(map[EventKey]FaultDetail),
+		NonFaultEventMap:  make(map[EventKey]NonFaultDetail),
+		OwnerEventNameMap: make(map[EventKeyStr]EventKey),
+		DaemonNameMap:     make(map[int]string),
+	}
+	evtMap := make(map[EventKey]EvtDetail)
 	for _, daemon := range evtJson.DaemonEvents {
-		fMgr.logger.Debug(fmt.Sprintln("daemon.DaemonName:", daemon.DaemonName))
-		fMgr.DaemonList = append(fMgr.DaemonList, daemon.DaemonName)
+		evtMaps.DaemonList = append(evtMaps.DaemonList, daemon.DaemonName)
+		evtMaps.DaemonNameMap[int(daemon.DaemonId)] = daemon.DaemonName
 		for _, evt := range daemon.EventList {
 			fId := EventKey{
 				DaemonId: int(daemon.DaemonId),
@@ -162,10 +299,10 @@ func (fMgr *FaultManager) initFMgrDS() error {
 				OwnerName: daemon.DaemonName,
 				EventName: evt.EventName,
 			}
-			fMgr.OwnerEventNameMap[fName] = fId
+			evtMaps.OwnerEventNameMap[fName] = fId
 			evtEnt, exist := evtMap[fId]
 			if exist {
-				fMgr.logger.Err(fmt.Sprintln("Duplicate entry found"))
+				errList = append(errList, errors.New(fmt.Sprintln("Duplicate entry found:", daemon.DaemonName, evt.EventName, fId)))
 				continue
 			}
 			if evt.IsFault == true {
@@ -201,7 +338,7 @@ func (fMgr *FaultManager) initFMgrDS() error {
 			}
 			cEvt, exist := evtMap[cFId]
 			if !exist {
-				fMgr.logger.Err(fmt.Sprintln("No clearing event found for fault:", fId))
+				errList = append(errList, errors.New(fmt.Sprintln("No clearing event found for fault:", evt.OwnerName, evt.EventName, fId)))
 				continue
 			}
 
@@ -212,7 +349,7 @@ func (fMgr *FaultManager) initFMgrDS() error {
 
 	for fId, evt := range evtMap {
 		if evt.IsFault == true {
-			evtEnt, _ := fMgr.FaultEventMap[fId]
+			evtEnt, _ := evtMaps.FaultEventMap[fId]
 			evtEnt.RaiseFault = evt.RaiseFault
 			evtEnt.ClearingEventId = evt.ClearingEventId
 			evtEnt.ClearingDaemonId = evt.ClearingDaemonId
@@ -220,26 +357,92 @@ func (fMgr *FaultManager) initFMgrDS() error {
 			evtEnt.FaultEventName = evt.EventName
 			evtEnt.FaultSrcObjName = evt.SrcObjName
 			evtEnt.AlarmSeverity = evt.AlarmSeverity
-			fMgr.FaultEventMap[fId] = evtEnt
+			evtMaps.FaultEventMap[fId] = evtEnt
 			cFId := EventKey{
 				DaemonId: evtEnt.ClearingDaemonId,
 				EventId:  evtEnt.ClearingEventId,
 			}
-			cEvtEnt, _ := fMgr.NonFaultEventMap[cFId]
+			cEvtEnt, _ := evtMaps.NonFaultEventMap[cFId]
 			cEvtEnt.FaultOwnerId = fId.DaemonId
 			cEvtEnt.FaultEventId = fId.EventId
-			fMgr.NonFaultEventMap[cFId] = cEvtEnt
+			evtMaps.NonFaultEventMap[cFId] = cEvtEnt
 		} else {
-			evtEnt, _ := fMgr.NonFaultEventMap[fId]
+			evtEnt, _ := evtMaps.NonFaultEventMap[fId]
 			evtEnt.IsClearingEvent = evt.IsClearingEvent
-			fMgr.NonFaultEventMap[fId] = evtEnt
+			evtMaps.NonFaultEventMap[fId] = evtEnt
 		}
 	}
-	return nil
+	return evtMaps, errList
+}
+
+// The event map getters below hold EvtMapRWMutex for the lookup only, the
+// event maps are replaced on reload of events.json. EvtMapRWMutex is not to
+// be held while calling them.
+func (fMgr *FaultManager) getFaultEvent(evtKey EventKey) (FaultDetail, bool) {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	fEnt, exist := fMgr.FaultEventMap[evtKey]
+	return fEnt, exist
+}
+
+func (fMgr *FaultManager) getNonFaultEvent(evtKey EventKey) (NonFaultDetail, bool) {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	ent, exist := fMgr.NonFaultEventMap[evtKey]
+	return ent, exist
+}
+
+func (fMgr *FaultManager) getEventKey(evtKeyStr EventKeyStr) (EventKey, bool) {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	evtKey, exist := fMgr.OwnerEventNameMap[evtKeyStr]
+	return evtKey, exist
+}
+
+func (fMgr *FaultManager) getDaemonName(daemonId int) (string, bool) {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	name, exist := fMgr.DaemonNameMap[daemonId]
+	return name, exist
+}
+
+// GetDaemonList returns the daemons having events defined in events.json
+func (fMgr *FaultManager) GetDaemonList() []string {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	return fMgr.DaemonList
+}
+
+// isEventOwner checks if the owner has any event defined
+func (fMgr *FaultManager) isEventOwner(ownerName string) bool {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	for evtKeyStr := range fMgr.OwnerEventNameMap {
+		if strings.EqualFold(evtKeyStr.OwnerName, ownerName) {
+			return true
+		}
+	}
+	return false
+}
+
+// getOwnerFaultEventKeys returns the keys of the faulty events of the owner
+func (fMgr *FaultManager) getOwnerFaultEventKeys(ownerName string) []EventKey {
+	fMgr.EvtMapRWMutex.RLock()
+	defer fMgr.EvtMapRWMutex.RUnlock()
+	var evtKeys []EventKey
+	for evtKeyStr, evtKey := range fMgr.OwnerEventNameMap {
+		if !strings.EqualFold(evtKeyStr.OwnerName, ownerName) {
+			continue
+		}
+		if _, exist := fMgr.FaultEventMap[evtKey]; exist {
+			evtKeys = append(evtKeys, evtKey)
+		}
+	}
+	return evtKeys
 }
 
 func (fMgr *FaultManager) faultEnable(evtKey EventKey, enable bool) (retVal bool, err error) {
-	_, exist := fMgr.FaultEventMap[evtKey]
+	_, exist := fMgr.getFaultEvent(evtKey)
 	if !exist {
 		err = errors.New("Unable to find the corresponding fault event")
 	} else {
@@ -264,23 +467,15 @@ func (fMgr *FaultManager) FaultEnableAction(config *objects.FaultEnable) (retVal
 	fMgr.PauseEventProcessCh <- true
 	<-fMgr.PauseEventProcessAckCh
 	if strings.ToLower(config.EventName) == objects.ALL_EVENTS {
-		ownerName := strings.ToLower(config.OwnerName)
-		for evtKeyStr, evtKey := range fMgr.OwnerEventNameMap {
-			if strings.ToLower(evtKeyStr.OwnerName) == ownerName {
-				_, exist := fMgr.FaultEventMap[evtKey]
-				if exist {
-					retVal, err = fMgr.faultEnable(evtKey, config.Enable)
-				}
-			} else {
-				continue
-			}
+		for _, evtKey := range fMgr.getOwnerFaultEventKeys(config.OwnerName) {
+			retVal, err = fMgr.faultEnable(evtKey, config.Enable)
 		}
 	} else {
 		evtKeyStr := EventKeyStr{
 			OwnerName: config.OwnerName,
 			EventName: config.EventName,
 		}
-		evtKey, exist := fMgr.OwnerEventNameMap[evtKeyStr]
+		evtKey, exist := fMgr.getEventKey(evtKeyStr)
 		if !exist {
 			err = errors.New("Unable to find the corresponding event")
 		} else {
@@ -292,6 +487,8 @@ func (fMgr *FaultManager) FaultEnableAction(config *objects.FaultEnable) (retVal
 }
 
 func (fMgr *FaultManager) DisableFaults(evtKey EventKey) error {
+	fMgr.EvtMapRWMutex.Lock()
+	defer fMgr.EvtMapRWMutex.Unlock()
 	fEnt, _ := fMgr.FaultEventMap[evtKey]
 	if fEnt.RaiseFault == false {
 		return errors.New("Fault is already disabled")
@@ -302,6 +499,8 @@ func (fMgr *FaultManager) DisableFaults(evtKey EventKey) error {
 }
 
 func (fMgr *FaultManager) EnableFaults(evtKey EventKey) error {
+	fMgr.EvtMapRWMutex.Lock()
+	defer fMgr.EvtMapRWMutex.Unlock()
 	fEnt, _ := fMgr.FaultEventMap[evtKey]
 	if fEnt.RaiseFault == true {
 		return errors.New("Fault is already enabled")
@@ -318,11 +517,11 @@ func (fMgr *FaultManager) FaultClearAction(config *objects.FaultClear) (retVal b
 		OwnerName: config.OwnerName,
 		EventName: config.EventName,
 	}
-	evtKey, exist := fMgr.OwnerEventNameMap[evtKeyStr]
+	evtKey, exist := fMgr.getEventKey(evtKeyStr)
 	if !exist {
 		err = errors.New("Unable to find the corresponding event")
 	} else {
-		fEnt, exist := fMgr.FaultEventMap[evtKey]
+		fEnt, exist := fMgr.getFaultEvent(evtKey)
 		if !exist {
 			err = errors.New("Unable to find the corresponding faulty event")
 		} else {
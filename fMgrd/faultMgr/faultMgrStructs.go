@@ -71,6 +71,7 @@ const (
 	AUTOCLEARED   Reason = 0
 	FAULTDISABLED Reason = 1
 	FAULTCLEARED  Reason = 2
+	EVENTREMOVED  Reason = 3
 )
 
 type FaultRBEntry struct {
@@ -84,6 +85,12 @@ type FaultRBEntry struct {
 	Resolved         bool
 	ResolutionReason Reason
 	SrcObjUUID       string
+	SrcObjAttrs      map[string]string
+	FlapCount        uint32
+	OccurrenceCount  uint32
+	LastSeenTime     time.Time
+	Injected         bool
+	InMaintenance    bool
 }
 
 type AlarmRBEntry struct {
@@ -97,21 +104,60 @@ type AlarmRBEntry struct {
 	Resolved         bool
 	ResolutionReason Reason
 	SrcObjUUID       string
+	Acknowledged     bool
+	AckedBy          string
+	AckTime          time.Time
+	AckNote          string
+	Flapping         bool
+	FlapCount        uint32
+	Severity         string
+	EscalationLevel  uint32
+	EscalationTime   time.Time
+	SrcObjAttrs      map[string]string
+	Suppressed       bool
+	SuppressedBy     string
+	SuppressedBySeq  uint64
+	OccurrenceCount  uint32
+	LastSeenTime     time.Time
+	Injected         bool
 }
 
 type FaultData struct {
 	FaultListIdx int
 	//AlarmListIdx     int
-	CreateAlarmTimer *time.Timer
+	CreateAlarmTimer Timer
 	FaultSeqNumber   uint64
+	LastPublishTime  time.Time
+	RepublishTimer   Timer
 }
 
 type AlarmData struct {
 	AlarmListIdx     int
 	AlarmSeqNumber   uint64
-	RemoveAlarmTimer *time.Timer
+	RemoveAlarmTimer Timer
+	EscalationTimer  Timer
+}
+
+type ShelveData struct {
+	ExpiryTime  time.Time
+	ExpiryTimer Timer
+}
+
+type FlapData struct {
+	Transitions    []time.Time
+	Flapping       bool
+	FlapCount      uint32
+	LastRaised     bool
+	LastTransition time.Time
+	QuietTimer     Timer
+	ObjKey         string
+	UUID           string
+	Attrs          map[string]string
+	Description    string
+	Injected       bool
 }
 
 type FaultObjKey string
 type FaultDataMap map[FaultObjKey]FaultData
 type AlarmDataMap map[FaultObjKey]AlarmData
+type ShelveDataMap map[string]ShelveData // Key is SrcObjUUID, "" stands for all objects
@@ -39,7 +39,7 @@ func (fMgr *FaultManager) GetFaultStateObject(fault *FaultRBEntry) (fObj objects
 		DaemonId: fault.OwnerId,
 		EventId:  fault.EventId,
 	}
-	fEnt, exist := fMgr.FaultEventMap[evtKey]
+	fEnt, exist := fMgr.getFaultEvent(evtKey)
 	if !exist {
 		return fObj, errors.New("Error finding the entry in Fault Event")
 	}
@@ -57,10 +57,16 @@ func (fMgr *FaultManager) GetFaultStateObject(fault *FaultRBEntry) (fObj objects
 		fObj.ResolutionTime = "N/A"
 		fObj.ResolutionReason = "N/A"
 	}
+	fObj.FlapCount = int32(fault.FlapCount)
+	fObj.OccurrenceCount = getOccurrenceCount(fault.OccurrenceCount)
+	fObj.LastSeenTime = getLastSeenTime(fault.LastSeenTime, fault.OccuranceTime)
+	fObj.SeqNumber = int64(fault.FaultSeqNumber)
+	fObj.Injected = fault.Injected
+	fObj.InMaintenance = fault.InMaintenance
 	return fObj, nil
 }
 
-func (fMgr *FaultManager) PublishFaults(idx int) {
+func (fMgr *FaultManager) PublishFaults(idx int, transition StateTransition) {
 	fMgr.FRBRWMutex.RLock()
 	fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(idx)
 	fMgr.FRBRWMutex.RUnlock()
@@ -72,12 +78,17 @@ func (fMgr *FaultManager) PublishFaults(idx int) {
 	}
 	msg, _ := json.Marshal(fObj)
 	channel := fObj.OwnerName + "Faults"
-	fMgr.FaultPubHdl.Publish("PUBLISH", channel, msg)
+	fMgr.FaultPubHdl.Publish(channel, msg)
+	fMgr.exportFault(fObj, transition)
 }
 
-func (fMgr *FaultManager) GetBulkFaultState(fromIdx int, count int) (*objects.FaultStateGetInfo, error) {
+func (fMgr *FaultManager) GetBulkFaultState(fromIdx int, count int, filter *objects.StateFilter) (*objects.FaultStateGetInfo, error) {
 	var retObj objects.FaultStateGetInfo
 
+	sFilter, err := compileStateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
 	fMgr.FRBRWMutex.RLock()
 	faults := fMgr.FaultRB.GetListOfEntriesFromRingBuffer()
 	fMgr.FRBRWMutex.RUnlock()
@@ -90,6 +101,9 @@ func (fMgr *FaultManager) GetBulkFaultState(fromIdx int, count int) (*objects.Fa
 	for i, j = 0, fromIdx; i < count && j < length; j++ {
 		fIntf := faults[length-j-1]
 		fault := fIntf.(FaultRBEntry)
+		if !fMgr.matchFault(sFilter, &fault) {
+			continue
+		}
 		fObj, err := fMgr.GetFaultStateObject(&fault)
 		if err != nil {
 			continue
@@ -97,6 +111,14 @@ func (fMgr *FaultManager) GetBulkFaultState(fromIdx int, count int) (*objects.Fa
 		fState[i] = fObj
 		i++
 	}
+	// Skip the entries filtered out so that More is only set when there
+	// is a matching entry left for the next page
+	for ; j < length; j++ {
+		fault := faults[length-j-1].(FaultRBEntry)
+		if fMgr.matchFault(sFilter, &fault) {
+			break
+		}
+	}
 	retObj.EndIdx = j
 	retObj.Count = i
 	if j != length {
@@ -106,15 +128,15 @@ func (fMgr *FaultManager) GetBulkFaultState(fromIdx int, count int) (*objects.Fa
 	return &retObj, nil
 }
 
-func (fMgr *FaultManager) processEvents(evt eventUtils.Event) error {
+func (fMgr *FaultManager) processEvents(evt FaultEvent) error {
 	evtKey := EventKey{
 		DaemonId: int(evt.OwnerId),
 		EventId:  int(evt.EvtId),
 	}
 
-	if fEnt, exist := fMgr.FaultEventMap[evtKey]; exist {
+	if fEnt, exist := fMgr.getFaultEvent(evtKey); exist {
 		if fEnt.RaiseFault == false {
-			return nil
+			return errFaultDisabled
 		}
 		err := fMgr.ProcessFaultyEvents(evt)
 		fMgr.logger.Debug(fmt.Sprintln("Fault Database:", fMgr.FaultMap))
@@ -123,9 +145,9 @@ func (fMgr *FaultManager) processEvents(evt eventUtils.Event) error {
 		fMgr.logger.Debug(fmt.Sprintln("Alarm Ring Buffer:", fMgr.AlarmRB.GetListOfEntriesFromRingBuffer()))
 		return err
 	}
-	if ent, exist := fMgr.NonFaultEventMap[evtKey]; exist {
+	if ent, exist := fMgr.getNonFaultEvent(evtKey); exist {
 		if ent.IsClearingEvent == true {
-			err := fMgr.ProcessFaultClearingEvents(evt)
+			err := fMgr.ProcessFaultClearingEvents(evt.Event)
 			fMgr.logger.Debug(fmt.Sprintln("Fault Database:", fMgr.FaultMap))
 			fMgr.logger.Debug(fmt.Sprintln("Alarm Database:", fMgr.AlarmMap))
 			fMgr.logger.Debug(fmt.Sprintln("Fault Ring Buffer:", fMgr.FaultRB.GetListOfEntriesFromRingBuffer()))
@@ -135,10 +157,10 @@ func (fMgr *FaultManager) processEvents(evt eventUtils.Event) error {
 		return nil
 	}
 
-	return errors.New("Unrecognized Event for Fault Manager")
+	return errUnknownEvent
 }
 
-func (fMgr *FaultManager) ProcessFaultyEvents(evt eventUtils.Event) error {
+func (fMgr *FaultManager) ProcessFaultyEvents(evt FaultEvent) error {
 	fMgr.logger.Debug(fmt.Sprintln("Processing Faulty Events:", evt))
 	return fMgr.CreateEntryInFaultAlarmDB(evt)
 }
@@ -148,56 +170,86 @@ func (fMgr *FaultManager) ProcessFaultClearingEvents(evt eventUtils.Event) error
 	return fMgr.DeleteEntryFromFaultAlarmDB(evt)
 }
 
-func (fMgr *FaultManager) AddFaultEntryInRB(evt eventUtils.Event, objKey, uuid string) int {
+func (fMgr *FaultManager) AddFaultEntryInRB(evtKey EventKey, objKey, uuid string, attrs map[string]string, description string, occuranceTime time.Time, injected bool) int {
 	fRBEnt := FaultRBEntry{
-		OwnerId:        int(evt.OwnerId),
-		EventId:        int(evt.EvtId),
-		OccuranceTime:  evt.TimeStamp,
-		FaultSeqNumber: fMgr.FaultSeqNumber,
-		SrcObjKey:      objKey,
-		Description:    evt.Description,
-		SrcObjUUID:     uuid,
+		OwnerId:         evtKey.DaemonId,
+		EventId:         evtKey.EventId,
+		OccuranceTime:   occuranceTime,
+		FaultSeqNumber:  fMgr.FaultSeqNumber,
+		SrcObjKey:       objKey,
+		Description:     description,
+		SrcObjUUID:      uuid,
+		SrcObjAttrs:     attrs,
+		OccurrenceCount: 1,
+		LastSeenTime:    occuranceTime,
+		Injected:        injected,
+		InMaintenance:   fMgr.isInMaintenance(evtKey, objKey),
 	}
 
+	retention := fMgr.getHistoryRetention()
 	fMgr.FRBRWMutex.Lock()
+	fMgr.makeRoomInFaultRB(retention)
 	idx, _ := fMgr.FaultRB.InsertIntoRingBuffer(fRBEnt)
+	fMgr.FaultRBCount++
+	fMgr.History.RecordFault(fRBEnt)
 	fMgr.FRBRWMutex.Unlock()
 	return idx
 }
 
-func (fMgr *FaultManager) CreateEntryInFaultAlarmDB(evt eventUtils.Event) error {
+func (fMgr *FaultManager) CreateEntryInFaultAlarmDB(evt FaultEvent) error {
 	evtKey := EventKey{
 		DaemonId: int(evt.OwnerId),
 		EventId:  int(evt.EvtId),
 	}
 
 	fMgr.FMapRWMutex.Lock()
-	if fMgr.FaultMap[evtKey] == nil {
-		fMgr.FaultMap[evtKey] = make(map[FaultObjKey]FaultData)
-	}
-
-	fDataMapEnt, _ := fMgr.FaultMap[evtKey]
 	fObjKey, fObjKeyUUId, objKey, err := fMgr.generateFaultObjKey(evt.OwnerName, evt.SrcObjName, evt.SrcObjKey)
 	if err != nil {
 		fMgr.FMapRWMutex.Unlock()
 		return errors.New("Error generating fault object key")
 	}
 
-	fDataEnt, exist := fDataMapEnt[fObjKey]
-	if exist {
-		fMgr.FMapRWMutex.Unlock()
+	_, exist := fMgr.FaultMap[evtKey][fObjKey]
+	if exist && !fMgr.isFlapping(evtKey, fObjKey) {
 		fMgr.logger.Info("Already have corresponding fault in fault database")
+		fMgr.recordRepeatFault(evtKey, fObjKey, evt.TimeStamp)
+		fMgr.FMapRWMutex.Unlock()
 		return nil
 	}
 
-	faultIdx := fMgr.AddFaultEntryInRB(evt, objKey, fObjKeyUUId)
-	if faultIdx == -1 {
+	attrs := getSrcObjAttrs(evt.SrcObjKey)
+	dampened := fMgr.recordFlapTransition(evtKey, fObjKey, objKey, fObjKeyUUId, attrs, evt.Description, true)
+	if flapData, exist := fMgr.FlapMap[evtKey][fObjKey]; exist {
+		flapData.Injected = evt.Injected
+	}
+	if dampened {
 		fMgr.FMapRWMutex.Unlock()
+		fMgr.logger.Debug(fmt.Sprintln("Fault is flapping, hence dampening", evt))
+		return nil
+	}
+
+	err = fMgr.raiseFault(evtKey, fObjKey, objKey, fObjKeyUUId, attrs, evt.Description, evt.TimeStamp, evt.Injected)
+	fMgr.FMapRWMutex.Unlock()
+	return err
+}
+
+// raiseFault adds the fault in fault database and starts the alarm timer.
+// Caller is expected to hold FMapRWMutex.
+func (fMgr *FaultManager) raiseFault(evtKey EventKey, fObjKey FaultObjKey, objKey, uuid string, attrs map[string]string, description string, occuranceTime time.Time, injected bool) error {
+	if fMgr.FaultMap[evtKey] == nil {
+		fMgr.FaultMap[evtKey] = make(map[FaultObjKey]FaultData)
+	}
+	fDataMapEnt, _ := fMgr.FaultMap[evtKey]
+	var fDataEnt FaultData
+
+	faultIdx := fMgr.AddFaultEntryInRB(evtKey, objKey, uuid, attrs, description, occuranceTime, injected)
+	if faultIdx == -1 {
 		return errors.New("Unable to add entry in fault database")
 	}
 
-	fMgr.PublishFaults(faultIdx)
+	fMgr.PublishFaults(faultIdx, TRANSITION_RAISED)
 
+	fDataEnt.LastPublishTime = fMgr.Clock.Now()
 	fDataEnt.FaultListIdx = faultIdx
 	fDataEnt.FaultSeqNumber = fMgr.FaultSeqNumber
 	fMgr.FaultSeqNumber++
@@ -207,12 +259,12 @@ func (fMgr *FaultManager) CreateEntryInFaultAlarmDB(evt eventUtils.Event) error
 	fMgr.AMapRWMutex.Lock()
 	aDataMapEnt, exist := fMgr.AlarmMap[evtKey]
 	if exist == false {
-		fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evt)
+		fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evtKey, fObjKey, objKey, uuid, attrs, description, injected)
 	} else {
 		aDataEnt, exist := aDataMapEnt[fObjKey]
 		if !exist {
-			fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evt)
-		} else {
+			fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evtKey, fObjKey, objKey, uuid, attrs, description, injected)
+		} else if aDataEnt.RemoveAlarmTimer != nil {
 			ret := aDataEnt.RemoveAlarmTimer.Stop()
 			if ret == true {
 				fMgr.logger.Debug("Alarm corresponding to event cannot be removed as we received a fault again")
@@ -226,7 +278,6 @@ func (fMgr *FaultManager) CreateEntryInFaultAlarmDB(evt eventUtils.Event) error
 	fMgr.AMapRWMutex.Unlock()
 	fDataMapEnt[fObjKey] = fDataEnt
 	fMgr.FaultMap[evtKey] = fDataMapEnt
-	fMgr.FMapRWMutex.Unlock()
 	return nil
 }
 
@@ -236,7 +287,7 @@ func (fMgr *FaultManager) DeleteEntryFromFaultAlarmDB(evt eventUtils.Event) erro
 		EventId:  int(evt.EvtId),
 	}
 
-	cFEnt, exist := fMgr.NonFaultEventMap[evtKey]
+	cFEnt, exist := fMgr.getNonFaultEvent(evtKey)
 	if !exist {
 		return errors.New("Error finding the fault for fault clearing event")
 	}
@@ -245,74 +296,91 @@ func (fMgr *FaultManager) DeleteEntryFromFaultAlarmDB(evt eventUtils.Event) erro
 		EventId:  cFEnt.FaultEventId,
 	}
 
-	if fEnt, exist := fMgr.FaultEventMap[fEvtKey]; exist {
+	if fEnt, exist := fMgr.getFaultEvent(fEvtKey); exist {
 		if fEnt.RaiseFault == false {
 			return nil
 		}
 	} else {
 		return errors.New("Unbale to find the corresponding fault Event")
 	}
-	fObjKey, _, _, err := fMgr.generateFaultObjKey(evt.OwnerName, evt.SrcObjName, evt.SrcObjKey)
+	fObjKey, fObjKeyUUId, objKey, err := fMgr.generateFaultObjKey(evt.OwnerName, evt.SrcObjName, evt.SrcObjKey)
 	if err != nil {
 		return errors.New("Error generating fault object key")
 	}
 
 	fMgr.FMapRWMutex.Lock()
-	fDataMapEnt, exist := fMgr.FaultMap[fEvtKey]
-	if !exist {
+	_, exist = fMgr.FaultMap[fEvtKey][fObjKey]
+	if !exist && !fMgr.isFlapping(fEvtKey, fObjKey) {
 		fMgr.logger.Debug(fmt.Sprintln("No such fault occured to be cleared, no entry faound in fault database", evt))
 		fMgr.FMapRWMutex.Unlock()
 		return nil
 	}
 
-	fDataEnt, exist := fDataMapEnt[fObjKey]
-	if !exist {
-		fMgr.logger.Debug(fmt.Sprintln("No such fault occured to be cleared, no entry faound in fault data", evt))
+	if fMgr.recordFlapTransition(fEvtKey, fObjKey, objKey, fObjKeyUUId, nil, "", false) {
 		fMgr.FMapRWMutex.Unlock()
+		fMgr.logger.Debug(fmt.Sprintln("Fault is flapping, hence dampening", evt))
 		return nil
 	}
+
+	fMgr.clearFault(fEvtKey, fObjKey, evt.TimeStamp)
+	fMgr.FMapRWMutex.Unlock()
+	return nil
+}
+
+// clearFault resolves the fault in fault database and starts the alarm
+// removal timer. Caller is expected to hold FMapRWMutex.
+func (fMgr *FaultManager) clearFault(fEvtKey EventKey, fObjKey FaultObjKey, resolutionTime time.Time) {
+	fDataMapEnt, exist := fMgr.FaultMap[fEvtKey]
+	if !exist {
+		return
+	}
+	fDataEnt, exist := fDataMapEnt[fObjKey]
+	if !exist {
+		return
+	}
 	fMgr.FRBRWMutex.RLock()
 	fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
 	fMgr.FRBRWMutex.RUnlock()
 	fDBKey := fIntf.(FaultRBEntry)
 	if fDataEnt.FaultSeqNumber == fDBKey.FaultSeqNumber {
-		fDBKey.ResolutionTime = evt.TimeStamp
+		fDBKey.ResolutionTime = resolutionTime
 		fDBKey.Resolved = true
 		fDBKey.ResolutionReason = AUTOCLEARED
 		fMgr.FRBRWMutex.Lock()
 		fMgr.FaultRB.UpdateEntryInRingBuffer(fDBKey, fDataEnt.FaultListIdx)
+		fMgr.History.RecordFault(fDBKey)
 		fMgr.FRBRWMutex.Unlock()
-		fMgr.PublishFaults(fDataEnt.FaultListIdx)
+		fMgr.PublishFaults(fDataEnt.FaultListIdx, TRANSITION_CLEARED)
 		fMgr.AMapRWMutex.Lock()
 		aDataMapEnt, exist := fMgr.AlarmMap[fEvtKey]
 		if !exist {
-			ret := fDataEnt.CreateAlarmTimer.Stop()
-			if ret == true {
-				fMgr.logger.Debug(fmt.Sprintln("Alarm timer is stopped for", evt))
+			if fDataEnt.CreateAlarmTimer != nil && fDataEnt.CreateAlarmTimer.Stop() {
+				fMgr.logger.Debug(fmt.Sprintln("Alarm timer is stopped for", fObjKey))
 			}
 		} else {
 			aDataEnt, exist := aDataMapEnt[fObjKey]
 			if !exist {
-				ret := fDataEnt.CreateAlarmTimer.Stop()
-				if ret == true {
-					fMgr.logger.Debug(fmt.Sprintln("Alarm timer is stopped for", evt))
+				if fDataEnt.CreateAlarmTimer != nil && fDataEnt.CreateAlarmTimer.Stop() {
+					fMgr.logger.Debug(fmt.Sprintln("Alarm timer is stopped for", fObjKey))
 				}
 			} else {
-				aDataEnt.RemoveAlarmTimer = fMgr.StartAlarmRemoveTimer(evt, AUTOCLEARED)
+				aDataEnt.RemoveAlarmTimer = fMgr.StartAlarmRemoveTimer(fEvtKey, fObjKey, AUTOCLEARED)
 				aDataMapEnt[fObjKey] = aDataEnt
 				fMgr.AlarmMap[fEvtKey] = aDataMapEnt
 			}
 		}
 		fMgr.AMapRWMutex.Unlock()
+		if fDataEnt.RepublishTimer != nil {
+			fDataEnt.RepublishTimer.Stop()
+		}
 		delete(fDataMapEnt, fObjKey)
 		fMgr.FaultMap[fEvtKey] = fDataMapEnt
 	}
-	fMgr.FMapRWMutex.Unlock()
-	return nil
 }
 
 func (fMgr *FaultManager) ClearExistingFaults(evtKey EventKey, uuid string, reason Reason) {
 	fMgr.FMapRWMutex.Lock()
+	fMgr.clearFlapData(evtKey, uuid)
 	fDataMapEnt, exist := fMgr.FaultMap[evtKey]
 	if !exist {
 		fMgr.FMapRWMutex.Unlock()
@@ -326,19 +394,23 @@ func (fMgr *FaultManager) ClearExistingFaults(evtKey EventKey, uuid string, reas
 		if fDataEnt.FaultSeqNumber == fDBKey.FaultSeqNumber {
 			if uuid == "" || uuid == fDBKey.SrcObjUUID {
 				fDBKey.ResolutionReason = reason
-				fDBKey.ResolutionTime = time.Now()
+				fDBKey.ResolutionTime = fMgr.Clock.Now()
 				fDBKey.Resolved = true
 				fMgr.FaultRB.UpdateEntryInRingBuffer(fDBKey, fDataEnt.FaultListIdx)
+				fMgr.History.RecordFault(fDBKey)
 				if fDataEnt.CreateAlarmTimer != nil {
 					fDataEnt.CreateAlarmTimer.Stop()
 				}
+				if fDataEnt.RepublishTimer != nil {
+					fDataEnt.RepublishTimer.Stop()
+				}
 				delete(fDataMapEnt, fDataKey)
 			}
 		}
 		fMgr.FRBRWMutex.Unlock()
 		if fDataEnt.FaultSeqNumber == fDBKey.FaultSeqNumber {
 			if uuid == "" || uuid == fDBKey.SrcObjUUID {
-				fMgr.PublishFaults(fDataEnt.FaultListIdx)
+				fMgr.PublishFaults(fDataEnt.FaultListIdx, TRANSITION_CLEARED)
 			}
 		}
 	}
@@ -347,3 +419,66 @@ func (fMgr *FaultManager) ClearExistingFaults(evtKey EventKey, uuid string, reas
 	}
 	fMgr.FMapRWMutex.Unlock()
 }
+
+// GetFaultState returns the active fault of the event for the source object,
+// falling back to its most recent fault in the fault database. SrcObjUUID
+// can be left empty only if a single source object has faults of the event.
+func (fMgr *FaultManager) GetFaultState(ownerId, eventId int, ownerName, eventName, srcObjName, srcObjUUID string) (*objects.FaultState, error) {
+	evtKey, err := fMgr.getStateEventKey(ownerId, eventId, ownerName, eventName, srcObjName)
+	if err != nil {
+		return nil, err
+	}
+
+	var fault FaultRBEntry
+	found := false
+	ambiguous := false
+	fMgr.FMapRWMutex.RLock()
+	fMgr.FRBRWMutex.RLock()
+	for _, fDataEnt := range fMgr.FaultMap[evtKey] {
+		fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+		fEnt := fIntf.(FaultRBEntry)
+		if fEnt.FaultSeqNumber != fDataEnt.FaultSeqNumber ||
+			(srcObjUUID != "" && fEnt.SrcObjUUID != srcObjUUID) {
+			continue
+		}
+		if found {
+			ambiguous = true
+			break
+		}
+		fault = fEnt
+		found = true
+	}
+	if !found {
+		view := newRBView(fMgr.FaultRB, fMgr.FaultRBCount, faultSeqOf)
+		for idx := view.length - 1; idx >= 0; idx-- {
+			fEnt := view.entryAt(idx).(FaultRBEntry)
+			if fEnt.OwnerId != evtKey.DaemonId || fEnt.EventId != evtKey.EventId ||
+				(srcObjUUID != "" && fEnt.SrcObjUUID != srcObjUUID) {
+				continue
+			}
+			if !found {
+				fault = fEnt
+				found = true
+				if srcObjUUID != "" {
+					break
+				}
+			} else if fEnt.SrcObjUUID != fault.SrcObjUUID {
+				ambiguous = true
+				break
+			}
+		}
+	}
+	fMgr.FRBRWMutex.RUnlock()
+	fMgr.FMapRWMutex.RUnlock()
+	if ambiguous {
+		return nil, errors.New(fmt.Sprintln("Multiple source objects have faults for", ownerName, eventName, "SrcObjUUID is required"))
+	}
+	if !found {
+		return nil, errors.New(fmt.Sprintln("Unable to find the fault state for", ownerName, eventName, srcObjName, srcObjUUID))
+	}
+	fObj, err := fMgr.GetFaultStateObject(&fault)
+	if err != nil {
+		return nil, err
+	}
+	return &fObj, nil
+}
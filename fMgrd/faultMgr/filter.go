@@ -0,0 +1,159 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"infra/fMgrd/objects"
+	"strings"
+	"time"
+)
+
+type resolvedFilter uint8
+
+const (
+	MATCH_ALL        resolvedFilter = 0
+	MATCH_RESOLVED   resolvedFilter = 1
+	MATCH_UNRESOLVED resolvedFilter = 2
+)
+
+type stateFilter struct {
+	ownerName  string
+	eventName  string
+	severity   string
+	srcObjKey  string
+	srcObjUUID string
+	resolved   resolvedFilter
+	fromTime   time.Time
+	toTime     time.Time
+}
+
+func parseFilterTime(timeStr string) (time.Time, error) {
+	if timeStr == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, timeStr)
+}
+
+// compileStateFilter validates the filter provided by the client. A nil
+// filter matches everything.
+func compileStateFilter(filter *objects.StateFilter) (*stateFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	var err error
+	sFilter := &stateFilter{
+		ownerName:  filter.OwnerName,
+		eventName:  filter.EventName,
+		severity:   filter.Severity,
+		srcObjKey:  filter.SrcObjKey,
+		srcObjUUID: filter.SrcObjUUID,
+	}
+	switch strings.ToLower(filter.Resolved) {
+	case "", objects.FILTER_ALL:
+		sFilter.resolved = MATCH_ALL
+	case objects.FILTER_RESOLVED:
+		sFilter.resolved = MATCH_RESOLVED
+	case objects.FILTER_UNRESOLVED:
+		sFilter.resolved = MATCH_UNRESOLVED
+	default:
+		return nil, errors.New("Invalid Resolved filter value provided, it should be all, resolved or unresolved")
+	}
+	sFilter.fromTime, err = parseFilterTime(filter.FromTime)
+	if err != nil {
+		return nil, errors.New("Invalid FromTime filter value provided, expected RFC3339 format")
+	}
+	sFilter.toTime, err = parseFilterTime(filter.ToTime)
+	if err != nil {
+		return nil, errors.New("Invalid ToTime filter value provided, expected RFC3339 format")
+	}
+	return sFilter, nil
+}
+
+func (sFilter *stateFilter) matchEvent(fEnt FaultDetail, severity string) bool {
+	if sFilter.ownerName != "" && !strings.EqualFold(sFilter.ownerName, fEnt.FaultOwnerName) {
+		return false
+	}
+	if sFilter.eventName != "" && !strings.EqualFold(sFilter.eventName, fEnt.FaultEventName) {
+		return false
+	}
+	if sFilter.severity != "" && !strings.EqualFold(sFilter.severity, severity) {
+		return false
+	}
+	return true
+}
+
+func (sFilter *stateFilter) matchEntry(srcObjKey, srcObjUUID string, resolved bool, occuranceTime time.Time) bool {
+	if sFilter.srcObjKey != "" && sFilter.srcObjKey != srcObjKey {
+		return false
+	}
+	if sFilter.srcObjUUID != "" && sFilter.srcObjUUID != srcObjUUID {
+		return false
+	}
+	if sFilter.resolved == MATCH_RESOLVED && !resolved {
+		return false
+	}
+	if sFilter.resolved == MATCH_UNRESOLVED && resolved {
+		return false
+	}
+	if !sFilter.fromTime.IsZero() && occuranceTime.Before(sFilter.fromTime) {
+		return false
+	}
+	if !sFilter.toTime.IsZero() && occuranceTime.After(sFilter.toTime) {
+		return false
+	}
+	return true
+}
+
+func (fMgr *FaultManager) matchFault(sFilter *stateFilter, fault *FaultRBEntry) bool {
+	if sFilter == nil {
+		return true
+	}
+	evtKey := EventKey{
+		DaemonId: fault.OwnerId,
+		EventId:  fault.EventId,
+	}
+	fEnt, exist := fMgr.getFaultEvent(evtKey)
+	if !exist {
+		return false
+	}
+	return sFilter.matchEvent(fEnt, fEnt.AlarmSeverity) &&
+		sFilter.matchEntry(fault.SrcObjKey, fault.SrcObjUUID, fault.Resolved, fault.OccuranceTime)
+}
+
+func (fMgr *FaultManager) matchAlarm(sFilter *stateFilter, alarm *AlarmRBEntry) bool {
+	if sFilter == nil {
+		return true
+	}
+	evtKey := EventKey{
+		DaemonId: alarm.OwnerId,
+		EventId:  alarm.EventId,
+	}
+	fEnt, exist := fMgr.getFaultEvent(evtKey)
+	if !exist {
+		return false
+	}
+	return sFilter.matchEvent(fEnt, getAlarmSeverity(alarm, fEnt)) &&
+		sFilter.matchEntry(alarm.SrcObjKey, alarm.SrcObjUUID, alarm.Resolved, alarm.OccuranceTime)
+}
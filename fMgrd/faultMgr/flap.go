@@ -0,0 +1,305 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DEFAULT_FLAP_WINDOW       = time.Duration(60) * time.Second
+	DEFAULT_FLAP_THRESHOLD    = 10
+	DEFAULT_FLAP_QUIET_PERIOD = time.Duration(60) * time.Second
+	FLAP_DETECTION_DISABLED   = -1 // FlapThreshold value disabling flap detection
+)
+
+// getFlapThreshold converts the configured FlapThreshold, 0 stands for the
+// default threshold. The returned threshold is 0 when flap detection is
+// disabled.
+func getFlapThreshold(threshold int32) int {
+	switch threshold {
+	case 0:
+		return DEFAULT_FLAP_THRESHOLD
+	case FLAP_DETECTION_DISABLED:
+		return 0
+	}
+	return int(threshold)
+}
+
+// getFlapPeriod converts the configured FlapWindow or FlapQuietPeriod, 0
+// stands for the default period.
+func getFlapPeriod(period int32, defaultPeriod time.Duration) time.Duration {
+	if period == 0 {
+		return defaultPeriod
+	}
+	return time.Duration(period) * time.Second
+}
+
+func (fMgr *FaultManager) getFlapConfig() (window time.Duration, threshold int, quietPeriod time.Duration) {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	return fMgr.FlapWindow, fMgr.FlapThreshold, fMgr.FlapQuietPeriod
+}
+
+// Caller is expected to hold FMapRWMutex
+func (fMgr *FaultManager) isFlapping(evtKey EventKey, fObjKey FaultObjKey) bool {
+	flapData, exist := fMgr.FlapMap[evtKey][fObjKey]
+	return exist && flapData.Flapping
+}
+
+// recordFlapTransition records a raise or clear transition of the fault and
+// returns true if the transition has to be dampened, i.e. neither recorded in
+// fault database nor acted upon. Caller is expected to hold FMapRWMutex.
+func (fMgr *FaultManager) recordFlapTransition(evtKey EventKey, fObjKey FaultObjKey, objKey, uuid string, attrs map[string]string, description string, raised bool) bool {
+	window, threshold, quietPeriod := fMgr.getFlapConfig()
+	flapData, exist := fMgr.FlapMap[evtKey][fObjKey]
+	if threshold == 0 && (!exist || !flapData.Flapping) {
+		return false
+	}
+	if !exist {
+		if fMgr.FlapMap[evtKey] == nil {
+			fMgr.FlapMap[evtKey] = make(map[FaultObjKey]*FlapData)
+		}
+		flapData = &FlapData{
+			ObjKey: objKey,
+			UUID:   uuid,
+		}
+		fMgr.FlapMap[evtKey][fObjKey] = flapData
+	}
+	now := fMgr.Clock.Now()
+	flapData.LastTransition = now
+	flapData.LastRaised = raised
+	if description != "" {
+		flapData.Description = description
+	}
+	if attrs != nil {
+		flapData.Attrs = attrs
+	}
+
+	if flapData.Flapping {
+		flapData.FlapCount++
+		fMgr.updateFlapCount(evtKey, fObjKey, flapData.FlapCount)
+		return true
+	}
+
+	// Only keep the transitions within the flap window
+	transitions := flapData.Transitions[:0]
+	for _, tTime := range flapData.Transitions {
+		if now.Sub(tTime) < window {
+			transitions = append(transitions, tTime)
+		}
+	}
+	flapData.Transitions = append(transitions, now)
+	if len(flapData.Transitions) < threshold {
+		return false
+	}
+
+	fMgr.logger.Info(fmt.Sprintln("Fault is flapping, dampening transitions for:", evtKey, fObjKey))
+	flapData.Flapping = true
+	flapData.FlapCount = uint32(len(flapData.Transitions))
+	flapData.Transitions = nil
+	flapData.QuietTimer = fMgr.Clock.AfterFunc(quietPeriod, func() {
+		fMgr.releaseFlapDampening(evtKey, fObjKey)
+	})
+	if fDataEnt, exist := fMgr.FaultMap[evtKey][fObjKey]; exist {
+		if fDataEnt.CreateAlarmTimer != nil {
+			fDataEnt.CreateAlarmTimer.Stop()
+		}
+	}
+	fMgr.raiseFlapAlarm(evtKey, fObjKey, flapData)
+	return true
+}
+
+// raiseFlapAlarm raises a single alarm for the flapping fault, converting an
+// existing alarm of the same object if any. Caller is expected to hold
+// FMapRWMutex.
+func (fMgr *FaultManager) raiseFlapAlarm(evtKey EventKey, fObjKey FaultObjKey, flapData *FlapData) {
+	fMgr.AMapRWMutex.Lock()
+	defer fMgr.AMapRWMutex.Unlock()
+	if fMgr.AlarmMap[evtKey] == nil {
+		fMgr.AlarmMap[evtKey] = make(map[FaultObjKey]AlarmData)
+	}
+	aDataMapEnt, _ := fMgr.AlarmMap[evtKey]
+	aDataEnt, exist := aDataMapEnt[fObjKey]
+	if exist {
+		if aDataEnt.RemoveAlarmTimer != nil {
+			aDataEnt.RemoveAlarmTimer.Stop()
+			aDataEnt.RemoveAlarmTimer = nil
+			aDataMapEnt[fObjKey] = aDataEnt
+		}
+		fMgr.ARBRWMutex.Lock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		aRBData := aIntf.(AlarmRBEntry)
+		if aRBData.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+			aRBData.Flapping = true
+			aRBData.FlapCount = flapData.FlapCount
+			fMgr.AlarmRB.UpdateEntryInRingBuffer(aRBData, aDataEnt.AlarmListIdx)
+			fMgr.History.RecordAlarm(aRBData)
+		}
+		fMgr.ARBRWMutex.Unlock()
+		fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_NONE)
+		return
+	}
+	if fMgr.isAlarmShelved(evtKey, flapData.UUID) {
+		fMgr.logger.Debug("Alarm is shelved, hence withholding flapping alarm for", evtKey, flapData.UUID)
+		return
+	}
+	if fMgr.isInMaintenance(evtKey, flapData.ObjKey) {
+		fMgr.logger.Debug("Object is in maintenance, hence withholding flapping alarm for", evtKey, flapData.ObjKey)
+		return
+	}
+	fEnt, _ := fMgr.getFaultEvent(evtKey)
+	aRBEnt := AlarmRBEntry{
+		OwnerId:        evtKey.DaemonId,
+		EventId:        evtKey.EventId,
+		OccuranceTime:  fMgr.Clock.Now(),
+		SrcObjKey:      flapData.ObjKey,
+		SrcObjUUID:     flapData.UUID,
+		SrcObjAttrs:    flapData.Attrs,
+		AlarmSeqNumber: fMgr.AlarmSeqNumber,
+		Description:    flapData.Description,
+		Flapping:       true,
+		FlapCount:      flapData.FlapCount,
+		Severity:       fEnt.AlarmSeverity,
+		Injected:       flapData.Injected,
+	}
+	fMgr.markSuppression(evtKey, &aRBEnt)
+	aDataEnt.AlarmListIdx = fMgr.insertAlarmEntryInRB(aRBEnt)
+	fMgr.updateAlarmSummary(&aRBEnt, 1)
+	aDataEnt.EscalationTimer = fMgr.StartEscalationTimer(evtKey, fObjKey, &aRBEnt)
+	aDataEnt.AlarmSeqNumber = fMgr.AlarmSeqNumber
+	fMgr.AlarmSeqNumber++
+	aDataMapEnt[fObjKey] = aDataEnt
+	fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_RAISED)
+}
+
+// updateFlapCount refreshes the flap count on the fault and alarm entries of
+// the object without publishing them. Caller is expected to hold FMapRWMutex.
+func (fMgr *FaultManager) updateFlapCount(evtKey EventKey, fObjKey FaultObjKey, flapCount uint32) {
+	if fDataEnt, exist := fMgr.FaultMap[evtKey][fObjKey]; exist {
+		fMgr.FRBRWMutex.Lock()
+		fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+		fault := fIntf.(FaultRBEntry)
+		if fault.FaultSeqNumber == fDataEnt.FaultSeqNumber {
+			fault.FlapCount = flapCount
+			fMgr.FaultRB.UpdateEntryInRingBuffer(fault, fDataEnt.FaultListIdx)
+			fMgr.History.RecordFault(fault)
+		}
+		fMgr.FRBRWMutex.Unlock()
+	}
+	fMgr.AMapRWMutex.RLock()
+	if aDataEnt, exist := fMgr.AlarmMap[evtKey][fObjKey]; exist {
+		fMgr.ARBRWMutex.Lock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		alarm := aIntf.(AlarmRBEntry)
+		if alarm.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+			alarm.FlapCount = flapCount
+			fMgr.AlarmRB.UpdateEntryInRingBuffer(alarm, aDataEnt.AlarmListIdx)
+			fMgr.History.RecordAlarm(alarm)
+		}
+		fMgr.ARBRWMutex.Unlock()
+	}
+	fMgr.AMapRWMutex.RUnlock()
+}
+
+// releaseFlapDampening is called once the fault has been quiet for the flap
+// quiet period. The flapping alarm is turned back into a regular alarm and the
+// fault database is brought in line with the last reported transition.
+func (fMgr *FaultManager) releaseFlapDampening(evtKey EventKey, fObjKey FaultObjKey) {
+	_, _, quietPeriod := fMgr.getFlapConfig()
+	fMgr.FMapRWMutex.Lock()
+	defer fMgr.FMapRWMutex.Unlock()
+	flapData, exist := fMgr.FlapMap[evtKey][fObjKey]
+	if !exist || !flapData.Flapping {
+		return
+	}
+	if elapsed := fMgr.Clock.Now().Sub(flapData.LastTransition); elapsed < quietPeriod {
+		flapData.QuietTimer = fMgr.Clock.AfterFunc(quietPeriod-elapsed, func() {
+			fMgr.releaseFlapDampening(evtKey, fObjKey)
+		})
+		return
+	}
+	fMgr.logger.Info(fmt.Sprintln("Fault stopped flapping, releasing dampening for:", evtKey, fObjKey, "flap count:", flapData.FlapCount))
+	delete(fMgr.FlapMap[evtKey], fObjKey)
+	if len(fMgr.FlapMap[evtKey]) == 0 {
+		delete(fMgr.FlapMap, evtKey)
+	}
+
+	fMgr.AMapRWMutex.Lock()
+	aDataEnt, alarmExist := fMgr.AlarmMap[evtKey][fObjKey]
+	if alarmExist {
+		fMgr.ARBRWMutex.Lock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		alarm := aIntf.(AlarmRBEntry)
+		if alarm.AlarmSeqNumber == aDataEnt.AlarmSeqNumber {
+			alarm.Flapping = false
+			fMgr.AlarmRB.UpdateEntryInRingBuffer(alarm, aDataEnt.AlarmListIdx)
+			fMgr.History.RecordAlarm(alarm)
+		}
+		fMgr.ARBRWMutex.Unlock()
+	}
+	fMgr.AMapRWMutex.Unlock()
+	if alarmExist {
+		fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_NONE)
+	}
+
+	fDataEnt, faultExist := fMgr.FaultMap[evtKey][fObjKey]
+	if flapData.LastRaised {
+		if !faultExist {
+			err := fMgr.raiseFault(evtKey, fObjKey, flapData.ObjKey, flapData.UUID, flapData.Attrs, flapData.Description, fMgr.Clock.Now(), flapData.Injected)
+			if err != nil {
+				fMgr.logger.Err(fmt.Sprintln("Error raising fault after flap dampening:", err))
+			}
+		} else if !alarmExist {
+			fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evtKey, fObjKey, flapData.ObjKey, flapData.UUID, flapData.Attrs, flapData.Description, flapData.Injected)
+			fMgr.FaultMap[evtKey][fObjKey] = fDataEnt
+		}
+	} else {
+		if faultExist {
+			fMgr.clearFault(evtKey, fObjKey, fMgr.Clock.Now())
+		} else if alarmExist {
+			fMgr.AMapRWMutex.Lock()
+			aDataEnt.RemoveAlarmTimer = fMgr.StartAlarmRemoveTimer(evtKey, fObjKey, AUTOCLEARED)
+			fMgr.AlarmMap[evtKey][fObjKey] = aDataEnt
+			fMgr.AMapRWMutex.Unlock()
+		}
+	}
+}
+
+// clearFlapData drops the flap tracking of the event, for the given object
+// only when uuid is set. Caller is expected to hold FMapRWMutex.
+func (fMgr *FaultManager) clearFlapData(evtKey EventKey, uuid string) {
+	for fObjKey, flapData := range fMgr.FlapMap[evtKey] {
+		if uuid == "" || uuid == flapData.UUID {
+			if flapData.QuietTimer != nil {
+				flapData.QuietTimer.Stop()
+			}
+			delete(fMgr.FlapMap[evtKey], fObjKey)
+		}
+	}
+	if len(fMgr.FlapMap[evtKey]) == 0 {
+		delete(fMgr.FlapMap, evtKey)
+	}
+}
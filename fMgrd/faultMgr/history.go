@@ -0,0 +1,408 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"utils/logging"
+)
+
+const (
+	HISTORY_FILE = "/opt/flexswitch/fMgrd/faultAlarmHistory.journal"
+)
+
+type HistoryRecordType uint8
+
+const (
+	FAULT_RECORD HistoryRecordType = 0
+	ALARM_RECORD HistoryRecordType = 1
+)
+
+// Every insert or update of a fault/alarm ring buffer entry is appended to
+// the journal as one JSON line. On startup the journal is replayed, keeping
+// the last record seen for each sequence number.
+type HistoryRecord struct {
+	Type  HistoryRecordType
+	Fault *FaultRBEntry `json:",omitempty"`
+	Alarm *AlarmRBEntry `json:",omitempty"`
+}
+
+type HistoryJournal struct {
+	logger     logging.LoggerIntf
+	mutex      sync.Mutex
+	fileName   string
+	fd         *os.File
+	numRecords int
+	maxRecords int
+	disabled   bool
+	CompactCh  chan bool
+}
+
+func NewHistoryJournal(logger logging.LoggerIntf, fileName string, maxRecords int) *HistoryJournal {
+	return &HistoryJournal{
+		logger:     logger,
+		fileName:   fileName,
+		maxRecords: maxRecords,
+		CompactCh:  make(chan bool, 1),
+	}
+}
+
+func (journal *HistoryJournal) Load() (faults []FaultRBEntry, alarms []AlarmRBEntry, err error) {
+	fd, err := os.Open(journal.fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return faults, alarms, nil
+		}
+		return faults, alarms, err
+	}
+	defer fd.Close()
+
+	faultIdxMap := make(map[uint64]int)
+	alarmIdxMap := make(map[uint64]int)
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record HistoryRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			// A partially written last line is expected after a crash
+			journal.logger.Err(fmt.Sprintln("Skipping corrupted history record:", err))
+			continue
+		}
+		switch record.Type {
+		case FAULT_RECORD:
+			if record.Fault == nil {
+				continue
+			}
+			if idx, exist := faultIdxMap[record.Fault.FaultSeqNumber]; exist {
+				faults[idx] = *record.Fault
+			} else {
+				faultIdxMap[record.Fault.FaultSeqNumber] = len(faults)
+				faults = append(faults, *record.Fault)
+			}
+		case ALARM_RECORD:
+			if record.Alarm == nil {
+				continue
+			}
+			if idx, exist := alarmIdxMap[record.Alarm.AlarmSeqNumber]; exist {
+				alarms[idx] = *record.Alarm
+			} else {
+				alarmIdxMap[record.Alarm.AlarmSeqNumber] = len(alarms)
+				alarms = append(alarms, *record.Alarm)
+			}
+		}
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].FaultSeqNumber < faults[j].FaultSeqNumber
+	})
+	sort.Slice(alarms, func(i, j int) bool {
+		return alarms[i].AlarmSeqNumber < alarms[j].AlarmSeqNumber
+	})
+	return faults, alarms, scanner.Err()
+}
+
+// Rewrite replaces the journal with one record per entry, dropping the
+// superseded records accumulated by updates.
+func (journal *HistoryJournal) Rewrite(faults []FaultRBEntry, alarms []AlarmRBEntry) error {
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	if journal.disabled {
+		return errors.New("History journal is disabled")
+	}
+	err := os.MkdirAll(filepath.Dir(journal.fileName), 0755)
+	if err != nil {
+		return err
+	}
+	tmpFileName := journal.fileName + ".tmp"
+	fd, err := os.OpenFile(tmpFileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(fd)
+	for idx := range faults {
+		writeHistoryRecord(writer, HistoryRecord{Type: FAULT_RECORD, Fault: &faults[idx]})
+	}
+	for idx := range alarms {
+		writeHistoryRecord(writer, HistoryRecord{Type: ALARM_RECORD, Alarm: &alarms[idx]})
+	}
+	err = writer.Flush()
+	if err == nil {
+		err = fd.Sync()
+	}
+	fd.Close()
+	if err != nil {
+		os.Remove(tmpFileName)
+		return err
+	}
+	if journal.fd != nil {
+		journal.fd.Close()
+		journal.fd = nil
+	}
+	err = os.Rename(tmpFileName, journal.fileName)
+	if err != nil {
+		return err
+	}
+	journal.numRecords = len(faults) + len(alarms)
+	journal.fd, err = os.OpenFile(journal.fileName, os.O_APPEND|os.O_WRONLY, 0644)
+	return err
+}
+
+// Disable stops recording and rewriting the journal, the journal file is
+// left untouched.
+func (journal *HistoryJournal) Disable() {
+	journal.mutex.Lock()
+	journal.disabled = true
+	if journal.fd != nil {
+		journal.fd.Close()
+		journal.fd = nil
+	}
+	journal.mutex.Unlock()
+}
+
+// SetAside moves the journal out of the way, so that the records which could
+// not be loaded are not lost by the following Rewrite.
+func (journal *HistoryJournal) SetAside() (string, error) {
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+	backupFileName := fmt.Sprintf("%s.%d.bad", journal.fileName, time.Now().Unix())
+	return backupFileName, os.Rename(journal.fileName, backupFileName)
+}
+
+func writeHistoryRecord(writer *bufio.Writer, record HistoryRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	writer.Write(bytes)
+	return writer.WriteByte('\n')
+}
+
+func (journal *HistoryJournal) record(record HistoryRecord) {
+	if journal == nil {
+		return
+	}
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		journal.logger.Err(fmt.Sprintln("Unable to marshal history record:", err))
+		return
+	}
+	journal.mutex.Lock()
+	if journal.fd == nil {
+		journal.mutex.Unlock()
+		return
+	}
+	_, err = journal.fd.Write(append(bytes, '\n'))
+	if err != nil {
+		journal.logger.Err(fmt.Sprintln("Unable to write history record:", err))
+	}
+	journal.numRecords++
+	if journal.numRecords > journal.maxRecords {
+		select {
+		case journal.CompactCh <- true:
+		default:
+		}
+	}
+	journal.mutex.Unlock()
+}
+
+func (journal *HistoryJournal) SetMaxRecords(maxRecords int) {
+	journal.mutex.Lock()
+	journal.maxRecords = maxRecords
+	journal.mutex.Unlock()
+}
+
+// Compact requests the journal to be rewritten out of the ring buffers
+func (journal *HistoryJournal) Compact() {
+	select {
+	case journal.CompactCh <- true:
+	default:
+	}
+}
+
+// Caller is expected to hold FRBRWMutex
+func (journal *HistoryJournal) RecordFault(fault FaultRBEntry) {
+	journal.record(HistoryRecord{Type: FAULT_RECORD, Fault: &fault})
+}
+
+// Caller is expected to hold ARBRWMutex
+func (journal *HistoryJournal) RecordAlarm(alarm AlarmRBEntry) {
+	journal.record(HistoryRecord{Type: ALARM_RECORD, Alarm: &alarm})
+}
+
+// restoreHistory rebuilds the ring buffers, the active fault/alarm maps and
+// the sequence numbers from the journal. It must be called after initFMgrDS
+// and before EventProcessor is started.
+func (fMgr *FaultManager) restoreHistory() error {
+	faults, alarms, err := fMgr.History.Load()
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Error loading history journal, restoring partial history:", err))
+		backupFileName, err := fMgr.History.SetAside()
+		if err != nil {
+			// Rewriting would replace the journal with the partial history,
+			// leave it as is and do not record the history any further
+			fMgr.logger.Err(fmt.Sprintln("Unable to set aside the history journal, history will not be recorded:", err))
+			fMgr.History.Disable()
+		} else {
+			fMgr.logger.Err(fmt.Sprintln("History journal which failed to load is moved to", backupFileName))
+		}
+	}
+	if len(faults) > fMgr.FaultRBCapacity {
+		faults = faults[len(faults)-fMgr.FaultRBCapacity:]
+	}
+	if len(alarms) > fMgr.AlarmRBCapacity {
+		alarms = alarms[len(alarms)-fMgr.AlarmRBCapacity:]
+	}
+
+	fMgr.FMapRWMutex.Lock()
+	fMgr.AMapRWMutex.Lock()
+	for _, fault := range faults {
+		fMgr.FRBRWMutex.Lock()
+		idx, _ := fMgr.FaultRB.InsertIntoRingBuffer(fault)
+		fMgr.FRBRWMutex.Unlock()
+		if fault.FaultSeqNumber >= fMgr.FaultSeqNumber {
+			fMgr.FaultSeqNumber = fault.FaultSeqNumber + 1
+		}
+		if fault.Resolved == true || idx == -1 {
+			continue
+		}
+		evtKey := EventKey{
+			DaemonId: fault.OwnerId,
+			EventId:  fault.EventId,
+		}
+		fEnt, exist := fMgr.getFaultEvent(evtKey)
+		if !exist {
+			fMgr.logger.Err(fmt.Sprintln("Unable to restore fault, event no longer exist:", evtKey))
+			continue
+		}
+		if fMgr.FaultMap[evtKey] == nil {
+			fMgr.FaultMap[evtKey] = make(map[FaultObjKey]FaultData)
+		}
+		fObjKey := buildFaultObjKey(fEnt.FaultSrcObjName, fault.SrcObjKey, fault.SrcObjUUID)
+		fMgr.FaultMap[evtKey][fObjKey] = FaultData{
+			FaultListIdx:   idx,
+			FaultSeqNumber: fault.FaultSeqNumber,
+		}
+	}
+
+	for _, alarm := range alarms {
+		fMgr.ARBRWMutex.Lock()
+		idx, _ := fMgr.AlarmRB.InsertIntoRingBuffer(alarm)
+		fMgr.ARBRWMutex.Unlock()
+		if alarm.AlarmSeqNumber >= fMgr.AlarmSeqNumber {
+			fMgr.AlarmSeqNumber = alarm.AlarmSeqNumber + 1
+		}
+		if alarm.Resolved == true || idx == -1 {
+			continue
+		}
+		evtKey := EventKey{
+			DaemonId: alarm.OwnerId,
+			EventId:  alarm.EventId,
+		}
+		fEnt, exist := fMgr.getFaultEvent(evtKey)
+		if !exist {
+			fMgr.logger.Err(fmt.Sprintln("Unable to restore alarm, event no longer exist:", evtKey))
+			continue
+		}
+		if fMgr.AlarmMap[evtKey] == nil {
+			fMgr.AlarmMap[evtKey] = make(map[FaultObjKey]AlarmData)
+		}
+		fObjKey := buildFaultObjKey(fEnt.FaultSrcObjName, alarm.SrcObjKey, alarm.SrcObjUUID)
+		fMgr.AlarmMap[evtKey][fObjKey] = AlarmData{
+			AlarmListIdx:   idx,
+			AlarmSeqNumber: alarm.AlarmSeqNumber,
+			// Accounts for the time elapsed since the last escalation
+			EscalationTimer: fMgr.StartEscalationTimer(evtKey, fObjKey, &alarm),
+		}
+	}
+
+	// Resume the timers which were running when fMgrd went down: faults
+	// without an alarm are still waiting to be promoted and alarms without
+	// a fault are still waiting to be cleared.
+	for evtKey, fDataMapEnt := range fMgr.FaultMap {
+		for fObjKey, fDataEnt := range fDataMapEnt {
+			if _, exist := fMgr.AlarmMap[evtKey][fObjKey]; exist {
+				continue
+			}
+			fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+			fault := fIntf.(FaultRBEntry)
+			fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evtKey, fObjKey, fault.SrcObjKey, fault.SrcObjUUID, fault.SrcObjAttrs, fault.Description, fault.Injected)
+			fDataMapEnt[fObjKey] = fDataEnt
+		}
+	}
+	for evtKey, aDataMapEnt := range fMgr.AlarmMap {
+		for fObjKey, aDataEnt := range aDataMapEnt {
+			if _, exist := fMgr.FaultMap[evtKey][fObjKey]; exist {
+				continue
+			}
+			aDataEnt.RemoveAlarmTimer = fMgr.StartAlarmRemoveTimer(evtKey, fObjKey, AUTOCLEARED)
+			aDataMapEnt[fObjKey] = aDataEnt
+		}
+	}
+	fMgr.FaultRBCount = len(faults)
+	fMgr.AlarmRBCount = len(alarms)
+	fMgr.rebuildAlarmSummary()
+	fMgr.AMapRWMutex.Unlock()
+	fMgr.FMapRWMutex.Unlock()
+	fMgr.logger.Info(fmt.Sprintln("Restored", len(faults), "faults and", len(alarms), "alarms from history, FaultSeqNumber:", fMgr.FaultSeqNumber, "AlarmSeqNumber:", fMgr.AlarmSeqNumber))
+
+	return fMgr.History.Rewrite(faults, alarms)
+}
+
+func (fMgr *FaultManager) compactHistory() error {
+	fMgr.FRBRWMutex.RLock()
+	fMgr.ARBRWMutex.RLock()
+	defer fMgr.ARBRWMutex.RUnlock()
+	defer fMgr.FRBRWMutex.RUnlock()
+	fList := fMgr.FaultRB.GetListOfEntriesFromRingBuffer()
+	faults := make([]FaultRBEntry, 0, len(fList))
+	for _, fIntf := range fList {
+		faults = append(faults, fIntf.(FaultRBEntry))
+	}
+	aList := fMgr.AlarmRB.GetListOfEntriesFromRingBuffer()
+	alarms := make([]AlarmRBEntry, 0, len(aList))
+	for _, aIntf := range aList {
+		alarms = append(alarms, aIntf.(AlarmRBEntry))
+	}
+	return fMgr.History.Rewrite(faults, alarms)
+}
+
+func (fMgr *FaultManager) HistoryCompactor() {
+	for {
+		select {
+		case _ = <-fMgr.History.CompactCh:
+			err := fMgr.compactHistory()
+			if err != nil {
+				fMgr.logger.Err(fmt.Sprintln("Error compacting history journal:", err))
+			}
+		}
+	}
+}
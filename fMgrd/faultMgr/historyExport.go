@@ -0,0 +1,319 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	HISTORY_EXPORT_DIR        = "/opt/flexswitch/fMgrd/export"
+	HISTORY_EXPORT_CHUNK_SIZE = 1000 // Entries copied out of ring buffer at a time
+	EXPORT_FORMAT_JSON        = "json"
+	EXPORT_FORMAT_CSV         = "csv"
+	EXPORT_STATUS_IN_PROGRESS = "InProgress"
+)
+
+// Every exported entry is one JSON line in json format
+type historyExportRecord struct {
+	Fault *objects.FaultState `json:",omitempty"`
+	Alarm *objects.AlarmState `json:",omitempty"`
+}
+
+var historyExportCSVHeader = []string{
+	"Type", "SeqNumber", "OwnerName", "EventName", "SrcObjName", "SrcObjKey", "SrcObjUUID",
+	"Description", "Severity", "OccuranceTime", "ResolutionTime", "ResolutionReason", "Injected",
+}
+
+type historyExportWriter interface {
+	writeFault(fault *objects.FaultState) error
+	writeAlarm(alarm *objects.AlarmState) error
+	flush() error
+}
+
+type jsonExportWriter struct {
+	writer *bufio.Writer
+}
+
+func (w *jsonExportWriter) write(record historyExportRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	w.writer.Write(bytes)
+	return w.writer.WriteByte('\n')
+}
+
+func (w *jsonExportWriter) writeFault(fault *objects.FaultState) error {
+	return w.write(historyExportRecord{Fault: fault})
+}
+
+func (w *jsonExportWriter) writeAlarm(alarm *objects.AlarmState) error {
+	return w.write(historyExportRecord{Alarm: alarm})
+}
+
+func (w *jsonExportWriter) flush() error {
+	return w.writer.Flush()
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvExportWriter) writeFault(fault *objects.FaultState) error {
+	return w.writer.Write([]string{
+		"Fault", strconv.FormatInt(fault.SeqNumber, 10), fault.OwnerName, fault.EventName, fault.SrcObjName,
+		fault.SrcObjKey, fault.SrcObjUUID, fault.Description, "", fault.OccuranceTime,
+		fault.ResolutionTime, fault.ResolutionReason, strconv.FormatBool(fault.Injected),
+	})
+}
+
+func (w *csvExportWriter) writeAlarm(alarm *objects.AlarmState) error {
+	return w.writer.Write([]string{
+		"Alarm", strconv.FormatInt(alarm.SeqNumber, 10), alarm.OwnerName, alarm.EventName, alarm.SrcObjName,
+		alarm.SrcObjKey, alarm.SrcObjUUID, alarm.Description, alarm.Severity, alarm.OccuranceTime,
+		alarm.ResolutionTime, alarm.ResolutionReason, strconv.FormatBool(alarm.Injected),
+	})
+}
+
+func (w *csvExportWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func newHistoryExportWriter(format string, fd *os.File) (historyExportWriter, error) {
+	switch format {
+	case EXPORT_FORMAT_JSON:
+		return &jsonExportWriter{writer: bufio.NewWriter(fd)}, nil
+	case EXPORT_FORMAT_CSV:
+		writer := csv.NewWriter(fd)
+		return &csvExportWriter{writer: writer}, writer.Write(historyExportCSVHeader)
+	}
+	return nil, errors.New("Invalid Format provided, it should be json or csv")
+}
+
+func validateHistoryExportDir(dir string) error {
+	if dir != "" && !filepath.IsAbs(dir) {
+		return errors.New("Invalid HistoryExportDir value provided, it should be an absolute path")
+	}
+	return nil
+}
+
+func (fMgr *FaultManager) getHistoryExportDir() string {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	if fMgr.HistoryExportDir == "" {
+		return HISTORY_EXPORT_DIR
+	}
+	return fMgr.HistoryExportDir
+}
+
+// exportFaults writes the faults chunk by chunk, the fault ring buffer is
+// only locked while a chunk is copied out of it.
+func (fMgr *FaultManager) exportFaults(writer historyExportWriter, filter *objects.StateFilter) (count int, err error) {
+	cursor := &objects.StateCursor{
+		SeqNumber: -1,
+		Direction: objects.CURSOR_FORWARD,
+		Count:     HISTORY_EXPORT_CHUNK_SIZE,
+	}
+	for {
+		info, err := fMgr.GetFaultStateByCursor(cursor, filter)
+		if err != nil {
+			return count, err
+		}
+		for idx := range info.List {
+			err = writer.writeFault(&info.List[idx])
+			if err != nil {
+				return count, err
+			}
+		}
+		count += info.Count
+		if !info.More {
+			return count, nil
+		}
+		cursor.SeqNumber = info.NextSeqNumber
+	}
+}
+
+// exportAlarms writes the alarms chunk by chunk, the alarm ring buffer is
+// only locked while a chunk is copied out of it.
+func (fMgr *FaultManager) exportAlarms(writer historyExportWriter, filter *objects.StateFilter) (count int, err error) {
+	cursor := &objects.StateCursor{
+		SeqNumber: -1,
+		Direction: objects.CURSOR_FORWARD,
+		Count:     HISTORY_EXPORT_CHUNK_SIZE,
+	}
+	for {
+		info, err := fMgr.GetAlarmStateByCursor(cursor, filter)
+		if err != nil {
+			return count, err
+		}
+		for idx := range info.List {
+			err = writer.writeAlarm(&info.List[idx])
+			if err != nil {
+				return count, err
+			}
+		}
+		count += info.Count
+		if !info.More {
+			return count, nil
+		}
+		cursor.SeqNumber = info.NextSeqNumber
+	}
+}
+
+// exportHistory writes the fault history followed by the alarm history into
+// a temporary file which replaces the export file once complete.
+func (fMgr *FaultManager) exportHistory(fileName, format string, filter *objects.StateFilter, state *objects.HistoryExportState) error {
+	tmpFileName := fileName + ".tmp"
+	fd, err := os.Create(tmpFileName)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFileName)
+	writer, err := newHistoryExportWriter(format, fd)
+	if err == nil {
+		var count int
+		count, err = fMgr.exportFaults(writer, filter)
+		state.FaultCount = int32(count)
+	}
+	if err == nil {
+		var count int
+		count, err = fMgr.exportAlarms(writer, filter)
+		state.AlarmCount = int32(count)
+	}
+	if err == nil {
+		err = writer.flush()
+	}
+	closeErr := fd.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	return os.Rename(tmpFileName, fileName)
+}
+
+func (fMgr *FaultManager) HistoryExportAction(config *objects.HistoryExport) (bool, error) {
+	format := strings.ToLower(config.Format)
+	if format == "" {
+		format = EXPORT_FORMAT_JSON
+	}
+	if format != EXPORT_FORMAT_JSON && format != EXPORT_FORMAT_CSV {
+		return false, errors.New("Invalid Format provided, it should be json or csv")
+	}
+	if config.FileName == "" || config.FileName != filepath.Base(config.FileName) ||
+		config.FileName == "." || config.FileName == ".." {
+		return false, errors.New("Invalid FileName provided, it should be a file name without directory")
+	}
+	filter := &objects.StateFilter{
+		OwnerName: config.OwnerName,
+		FromTime:  config.FromTime,
+		ToTime:    config.ToTime,
+	}
+	_, err := compileStateFilter(filter)
+	if err != nil {
+		return false, err
+	}
+
+	dir := fMgr.getHistoryExportDir()
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return false, err
+	}
+	state := objects.HistoryExportState{
+		FileName:   config.FileName,
+		Path:       filepath.Join(dir, config.FileName),
+		Format:     format,
+		ExportTime: fMgr.Clock.Now().String(),
+		Status:     EXPORT_STATUS_IN_PROGRESS,
+	}
+	fMgr.ExportStateMutex.Lock()
+	defer fMgr.ExportStateMutex.Unlock()
+	if fMgr.ExportStateMap[config.FileName].Status == EXPORT_STATUS_IN_PROGRESS {
+		return false, errors.New(fmt.Sprintln("History export to", config.FileName, "is already in progress"))
+	}
+	fMgr.ExportStateMap[config.FileName] = state
+	go fMgr.runHistoryExport(state, filter)
+	return true, nil
+}
+
+// runHistoryExport writes the history export in the background, its outcome
+// is reported through HistoryExportState.
+func (fMgr *FaultManager) runHistoryExport(state objects.HistoryExportState, filter *objects.StateFilter) {
+	err := fMgr.exportHistory(state.Path, state.Format, filter, &state)
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Error exporting history to", state.Path, err))
+		state.Status = fmt.Sprintln("Failed:", err)
+		state.FaultCount = 0
+		state.AlarmCount = 0
+	} else {
+		state.Status = "Success"
+		fMgr.logger.Info(fmt.Sprintln("Exported", state.FaultCount, "faults and", state.AlarmCount, "alarms to", state.Path))
+	}
+	fMgr.ExportStateMutex.Lock()
+	fMgr.ExportStateMap[state.FileName] = state
+	fMgr.ExportStateMutex.Unlock()
+}
+
+func (fMgr *FaultManager) GetHistoryExportState(fileName string) (*objects.HistoryExportState, error) {
+	fMgr.ExportStateMutex.Lock()
+	defer fMgr.ExportStateMutex.Unlock()
+	state, exist := fMgr.ExportStateMap[fileName]
+	if !exist {
+		return nil, errors.New(fmt.Sprintln("No history export found for", fileName))
+	}
+	return &state, nil
+}
+
+func (fMgr *FaultManager) GetBulkHistoryExportState(fromIdx, count int) (*objects.HistoryExportStateGetInfo, error) {
+	var retObj objects.HistoryExportStateGetInfo
+	var fileNames []string
+	fMgr.ExportStateMutex.Lock()
+	defer fMgr.ExportStateMutex.Unlock()
+	for fileName := range fMgr.ExportStateMap {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	length := len(fileNames)
+	idx := fromIdx
+	for ; idx < length && retObj.Count < count; idx++ {
+		retObj.List = append(retObj.List, fMgr.ExportStateMap[fileNames[idx]])
+		retObj.Count++
+	}
+	retObj.EndIdx = idx
+	retObj.More = idx < length
+	return &retObj, nil
+}
@@ -0,0 +1,195 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"infra/fMgrd/objects"
+	"sort"
+	"strings"
+)
+
+// Policy applied when EventCh is full
+const (
+	OVERFLOW_BLOCK       = "block"       // Wait for the event processor
+	OVERFLOW_DROP_NEWEST = "drop-newest" // Drop the event being queued
+	OVERFLOW_DROP_OLDEST = "drop-oldest" // Drop the oldest queued event
+)
+
+const (
+	EVENT_CH_SIZE            = 1000
+	DEFAULT_OVERFLOW_POLICY  = OVERFLOW_BLOCK
+	UNKNOWN_EVENT_OWNER_NAME = "unknown"
+)
+
+var (
+	errUnknownEvent  = errors.New("Unrecognized Event for Fault Manager")
+	errFaultDisabled = errors.New("Fault is disabled for the Event")
+	errEventDropped  = errors.New("Event queue is full, event dropped")
+)
+
+// EventMsg is an event queued on EventCh along with the owner it has been
+// received from
+type EventMsg struct {
+	Owner string
+	Data  []byte
+}
+
+type IngestStats struct {
+	Received         int64
+	Processed        int64
+	UnmarshalErrors  int64
+	UnknownEvents    int64
+	EnableSuppressed int64
+	ChannelFull      int64
+	Dropped          int64
+}
+
+func validateOverflowPolicy(policy string) error {
+	switch strings.ToLower(policy) {
+	case "", OVERFLOW_BLOCK, OVERFLOW_DROP_NEWEST, OVERFLOW_DROP_OLDEST:
+		return nil
+	}
+	return errors.New("Invalid EventOverflowPolicy value provided, it should be block, drop-newest or drop-oldest")
+}
+
+func (fMgr *FaultManager) getOverflowPolicy() string {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	if fMgr.EventOverflowPolicy == "" {
+		return DEFAULT_OVERFLOW_POLICY
+	}
+	return fMgr.EventOverflowPolicy
+}
+
+func (fMgr *FaultManager) updateIngestStats(owner string, update func(stats *IngestStats)) {
+	if owner == "" {
+		owner = UNKNOWN_EVENT_OWNER_NAME
+	}
+	fMgr.IngestMutex.Lock()
+	stats, exist := fMgr.IngestStatsMap[owner]
+	if !exist {
+		stats = &IngestStats{}
+		fMgr.IngestStatsMap[owner] = stats
+	}
+	update(stats)
+	fMgr.IngestMutex.Unlock()
+}
+
+// EnqueueEvent queues the event received from owner for the event processor,
+// applying the overflow policy when EventCh is full. An error is returned if
+// the event got dropped. Owner is the daemon channel name.
+func (fMgr *FaultManager) EnqueueEvent(owner string, data []byte) error {
+	msg := EventMsg{
+		Owner: owner,
+		Data:  data,
+	}
+	fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.Received++ })
+	channelFull := false
+	for {
+		select {
+		case fMgr.EventCh <- msg:
+			return nil
+		default:
+		}
+		if !channelFull {
+			channelFull = true
+			fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.ChannelFull++ })
+		}
+		switch fMgr.getOverflowPolicy() {
+		case OVERFLOW_BLOCK:
+			fMgr.EventCh <- msg
+			return nil
+		case OVERFLOW_DROP_NEWEST:
+			fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.Dropped++ })
+			return errEventDropped
+		case OVERFLOW_DROP_OLDEST:
+			select {
+			case oldMsg := <-fMgr.EventCh:
+				fMgr.updateIngestStats(oldMsg.Owner, func(stats *IngestStats) { stats.Dropped++ })
+			default:
+			}
+		}
+	}
+}
+
+// countProcessedEvent accounts the outcome of processing one event, the
+// events of a disabled fault are not reported as error.
+func (fMgr *FaultManager) countProcessedEvent(owner string, err error) error {
+	switch err {
+	case nil:
+		fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.Processed++ })
+	case errFaultDisabled:
+		fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.EnableSuppressed++ })
+		return nil
+	case errUnknownEvent:
+		fMgr.updateIngestStats(owner, func(stats *IngestStats) { stats.UnknownEvents++ })
+	}
+	return err
+}
+
+func getEventIngestStateObject(owner string, stats *IngestStats) objects.EventIngestState {
+	return objects.EventIngestState{
+		OwnerName:        owner,
+		Received:         stats.Received,
+		Processed:        stats.Processed,
+		UnmarshalErrors:  stats.UnmarshalErrors,
+		UnknownEvents:    stats.UnknownEvents,
+		EnableSuppressed: stats.EnableSuppressed,
+		ChannelFull:      stats.ChannelFull,
+		Dropped:          stats.Dropped,
+	}
+}
+
+func (fMgr *FaultManager) GetEventIngestState(ownerName string) (*objects.EventIngestState, error) {
+	fMgr.IngestMutex.Lock()
+	defer fMgr.IngestMutex.Unlock()
+	for owner, stats := range fMgr.IngestStatsMap {
+		if strings.EqualFold(owner, ownerName) {
+			state := getEventIngestStateObject(owner, stats)
+			return &state, nil
+		}
+	}
+	return nil, errors.New("No events received from the owner")
+}
+
+func (fMgr *FaultManager) GetBulkEventIngestState(fromIdx, count int) (*objects.EventIngestStateGetInfo, error) {
+	var retObj objects.EventIngestStateGetInfo
+	fMgr.IngestMutex.Lock()
+	defer fMgr.IngestMutex.Unlock()
+	var owners []string
+	for owner := range fMgr.IngestStatsMap {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	length := len(owners)
+	idx := fromIdx
+	for ; idx < length && retObj.Count < count; idx++ {
+		retObj.List = append(retObj.List, getEventIngestStateObject(owners[idx], fMgr.IngestStatsMap[owners[idx]]))
+		retObj.Count++
+	}
+	retObj.EndIdx = idx
+	retObj.More = idx < length
+	return &retObj, nil
+}
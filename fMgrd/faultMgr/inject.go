@@ -0,0 +1,122 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"utils/eventUtils"
+)
+
+// FaultEvent is the event as queued on EventCh. Injected is set only for the
+// events synthesized by FaultInjectAction.
+type FaultEvent struct {
+	eventUtils.Event
+	Injected bool
+}
+
+// getEventSrcObjName returns the source object name of the fault raised or
+// cleared by the given event.
+func (fMgr *FaultManager) getEventSrcObjName(evtKey EventKey) (string, error) {
+	if fEnt, exist := fMgr.getFaultEvent(evtKey); exist {
+		return fEnt.FaultSrcObjName, nil
+	}
+	ent, exist := fMgr.getNonFaultEvent(evtKey)
+	if !exist || ent.IsClearingEvent == false {
+		return "", errors.New("Event is neither a fault nor a fault clearing event")
+	}
+	fEvtKey := EventKey{
+		DaemonId: ent.FaultOwnerId,
+		EventId:  ent.FaultEventId,
+	}
+	fEnt, exist := fMgr.getFaultEvent(fEvtKey)
+	if !exist {
+		return "", errors.New("Unable to find the fault cleared by this event")
+	}
+	return fEnt.FaultSrcObjName, nil
+}
+
+func (fMgr *FaultManager) FaultInjectAction(config *objects.FaultInject) (bool, error) {
+	evtKeyStr := EventKeyStr{
+		OwnerName: config.OwnerName,
+		EventName: config.EventName,
+	}
+	evtKey, exist := fMgr.getEventKey(evtKeyStr)
+	if !exist {
+		return false, errors.New("Unable to find the corresponding event")
+	}
+	if fEnt, exist := fMgr.getFaultEvent(evtKey); exist && fEnt.RaiseFault == false {
+		return false, errors.New("Fault for this Event is disabled, hence cannot be injected")
+	}
+	srcObjName, err := fMgr.getEventSrcObjName(evtKey)
+	if err != nil {
+		return false, err
+	}
+	var srcObjKey map[string]interface{}
+	err = json.Unmarshal([]byte(config.SrcObjKey), &srcObjKey)
+	if err != nil {
+		return false, errors.New(fmt.Sprintln("Invalid SrcObjKey", config.SrcObjKey, err))
+	}
+	_, _, err = getEventObjKey(config.OwnerName, srcObjName, srcObjKey)
+	if err != nil {
+		return false, errors.New(fmt.Sprintln("Unable to find the ObjKey of", srcObjName, config.SrcObjKey, err))
+	}
+
+	evt := FaultEvent{
+		Event: eventUtils.Event{
+			OwnerId:     eventUtils.OwnerId(evtKey.DaemonId),
+			OwnerName:   config.OwnerName,
+			EvtId:       eventUtils.EventId(evtKey.EventId),
+			EventName:   config.EventName,
+			TimeStamp:   fMgr.Clock.Now(),
+			Description: config.Description,
+			SrcObjName:  srcObjName,
+			SrcObjKey:   srcObjKey,
+		},
+		Injected: true,
+	}
+	fMgr.logger.Info(fmt.Sprintln("Injecting event:", evt))
+	err = fMgr.queueEvent(evt)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// queueEvent hands over the event generated within fault manager to the
+// event processor, same as the events received from the daemons. The event
+// is accounted under the daemon channel name, as the daemon events are.
+func (fMgr *FaultManager) queueEvent(evt FaultEvent) error {
+	msg, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	owner, exist := fMgr.getDaemonName(int(evt.OwnerId))
+	if !exist {
+		return errors.New(fmt.Sprintln("Unable to find the daemon of the event", evt.OwnerName, evt.EventName))
+	}
+	return fMgr.EnqueueEvent(owner, msg)
+}
@@ -0,0 +1,291 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow withholds the alarms of an owner, or of one source object
+// of the owner when SrcObjKey is set, between StartTime and EndTime. A non
+// zero Recurrence repeats the window every Recurrence after StartTime.
+type MaintenanceWindow struct {
+	OwnerName  string
+	SrcObjKey  string
+	ObjKeys    map[string]bool // SrcObjKey resolved for every source object of the owner
+	StartTime  time.Time
+	EndTime    time.Time
+	Recurrence time.Duration
+	EndTimer   Timer
+}
+
+func (window *MaintenanceWindow) matches(ownerName, objKey string) bool {
+	if !strings.EqualFold(window.OwnerName, ownerName) {
+		return false
+	}
+	return window.SrcObjKey == "" || window.ObjKeys[objKey]
+}
+
+func (window *MaintenanceWindow) isActive(now time.Time) bool {
+	if now.Before(window.StartTime) {
+		return false
+	}
+	start := window.StartTime
+	if window.Recurrence > 0 {
+		start = start.Add(now.Sub(start) / window.Recurrence * window.Recurrence)
+	}
+	return now.Before(start.Add(window.EndTime.Sub(window.StartTime)))
+}
+
+// nextEndTime returns the end of the current or the next occurrence of the
+// window, zero time if the window is not going to end anymore.
+func (window *MaintenanceWindow) nextEndTime(now time.Time) time.Time {
+	if window.Recurrence == 0 || now.Before(window.StartTime) {
+		if now.Before(window.EndTime) {
+			return window.EndTime
+		}
+		return time.Time{}
+	}
+	start := window.StartTime.Add(now.Sub(window.StartTime) / window.Recurrence * window.Recurrence)
+	end := start.Add(window.EndTime.Sub(window.StartTime))
+	if !now.Before(end) {
+		end = end.Add(window.Recurrence)
+	}
+	return end
+}
+
+// getMaintenanceObjKeys converts SrcObjKey, given in JSON as in FaultInject,
+// into the ObjKey of the faults for every source object of the owner it
+// resolves for.
+func (fMgr *FaultManager) getMaintenanceObjKeys(ownerName, srcObjKeyStr string) (map[string]bool, error) {
+	objKeys := make(map[string]bool)
+	if srcObjKeyStr == "" {
+		return objKeys, nil
+	}
+	var srcObjKey map[string]interface{}
+	err := json.Unmarshal([]byte(srcObjKeyStr), &srcObjKey)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintln("Invalid SrcObjKey", srcObjKeyStr, err))
+	}
+	srcObjNames := make(map[string]bool)
+	for _, evtKey := range fMgr.getOwnerFaultEventKeys(ownerName) {
+		fEnt, _ := fMgr.getFaultEvent(evtKey)
+		srcObjNames[fEnt.FaultSrcObjName] = true
+	}
+	for srcObjName := range srcObjNames {
+		objKey, _, err := getEventObjKey(ownerName, srcObjName, srcObjKey)
+		if err == nil {
+			objKeys[objKey] = true
+		}
+	}
+	if len(objKeys) == 0 {
+		return nil, errors.New(fmt.Sprintln("Unable to find the ObjKey of", srcObjKeyStr, "for any faulty event of the owner", ownerName))
+	}
+	return objKeys, nil
+}
+
+func (fMgr *FaultManager) validateMaintenanceWindow(config *objects.MaintenanceWindow) (*MaintenanceWindow, error) {
+	if config.Name == "" {
+		return nil, errors.New("Invalid Name provided for maintenance window")
+	}
+	if !fMgr.isEventOwner(config.OwnerName) {
+		return nil, errors.New(fmt.Sprintln("Unable to find any event for the owner", config.OwnerName))
+	}
+	startTime, err := time.Parse(time.RFC3339, config.StartTime)
+	if err != nil {
+		return nil, errors.New("Invalid StartTime value provided, expected RFC3339 format")
+	}
+	endTime, err := time.Parse(time.RFC3339, config.EndTime)
+	if err != nil {
+		return nil, errors.New("Invalid EndTime value provided, expected RFC3339 format")
+	}
+	if !endTime.After(startTime) {
+		return nil, errors.New("EndTime should be after StartTime")
+	}
+	if config.RecurrenceInterval < 0 {
+		return nil, errors.New("Invalid RecurrenceInterval value provided")
+	}
+	recurrence := time.Duration(config.RecurrenceInterval) * time.Second
+	if recurrence > 0 && endTime.Sub(startTime) >= recurrence {
+		return nil, errors.New("RecurrenceInterval should be longer than the maintenance window")
+	}
+	objKeys, err := fMgr.getMaintenanceObjKeys(config.OwnerName, config.SrcObjKey)
+	if err != nil {
+		return nil, err
+	}
+	return &MaintenanceWindow{
+		OwnerName:  config.OwnerName,
+		SrcObjKey:  config.SrcObjKey,
+		ObjKeys:    objKeys,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Recurrence: recurrence,
+	}, nil
+}
+
+// isInMaintenance checks if any maintenance window covering the fault
+// event and source object is active right now.
+func (fMgr *FaultManager) isInMaintenance(evtKey EventKey, objKey string) bool {
+	fEnt, exist := fMgr.getFaultEvent(evtKey)
+	if !exist {
+		return false
+	}
+	now := fMgr.Clock.Now()
+	fMgr.MaintenanceRWMutex.RLock()
+	defer fMgr.MaintenanceRWMutex.RUnlock()
+	for _, window := range fMgr.MaintenanceMap {
+		if window.matches(fEnt.FaultOwnerName, objKey) && window.isActive(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Caller is expected to hold MaintenanceRWMutex
+func (fMgr *FaultManager) armMaintenanceEndTimer(name string, window *MaintenanceWindow) {
+	now := fMgr.Clock.Now()
+	endTime := window.nextEndTime(now)
+	if endTime.IsZero() {
+		window.EndTimer = nil
+		return
+	}
+	window.EndTimer = fMgr.Clock.AfterFunc(endTime.Sub(now), func() {
+		fMgr.endMaintenanceWindow(name, window)
+	})
+}
+
+func (fMgr *FaultManager) endMaintenanceWindow(name string, window *MaintenanceWindow) {
+	fMgr.MaintenanceRWMutex.Lock()
+	if fMgr.MaintenanceMap[name] != window {
+		fMgr.MaintenanceRWMutex.Unlock()
+		return
+	}
+	fMgr.armMaintenanceEndTimer(name, window)
+	fMgr.MaintenanceRWMutex.Unlock()
+	fMgr.logger.Info(fmt.Sprintln("Maintenance window ended:", name))
+	fMgr.promoteMaintenanceFaults(window)
+}
+
+// promoteMaintenanceFaults starts the alarm timer of every existing fault
+// covered by the window which does not have an alarm yet, unless another
+// window still covers it.
+func (fMgr *FaultManager) promoteMaintenanceFaults(window *MaintenanceWindow) {
+	fMgr.FMapRWMutex.Lock()
+	fMgr.AMapRWMutex.RLock()
+	for evtKey, fDataMapEnt := range fMgr.FaultMap {
+		fEnt, exist := fMgr.getFaultEvent(evtKey)
+		if !exist || !strings.EqualFold(fEnt.FaultOwnerName, window.OwnerName) {
+			continue
+		}
+		aDataMapEnt, _ := fMgr.AlarmMap[evtKey]
+		for fObjKey, fDataEnt := range fDataMapEnt {
+			if _, exist := aDataMapEnt[fObjKey]; exist {
+				continue
+			}
+			fMgr.FRBRWMutex.RLock()
+			fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+			fMgr.FRBRWMutex.RUnlock()
+			fault := fIntf.(FaultRBEntry)
+			if fault.FaultSeqNumber != fDataEnt.FaultSeqNumber {
+				continue
+			}
+			if !window.matches(fEnt.FaultOwnerName, fault.SrcObjKey) || fMgr.isInMaintenance(evtKey, fault.SrcObjKey) {
+				continue
+			}
+			if fDataEnt.CreateAlarmTimer != nil {
+				fDataEnt.CreateAlarmTimer.Stop()
+			}
+			fDataEnt.CreateAlarmTimer = fMgr.StartAlarmTimer(evtKey, fObjKey, fault.SrcObjKey, fault.SrcObjUUID, fault.SrcObjAttrs, fault.Description, fault.Injected)
+			fDataMapEnt[fObjKey] = fDataEnt
+		}
+	}
+	fMgr.AMapRWMutex.RUnlock()
+	fMgr.FMapRWMutex.Unlock()
+}
+
+// setMaintenanceWindow adds or replaces the window. The faults of the window
+// being replaced are promoted if the new window does not cover them anymore.
+func (fMgr *FaultManager) setMaintenanceWindow(name string, window *MaintenanceWindow) {
+	fMgr.MaintenanceRWMutex.Lock()
+	oldWindow, exist := fMgr.MaintenanceMap[name]
+	if exist && oldWindow.EndTimer != nil {
+		oldWindow.EndTimer.Stop()
+	}
+	if window == nil {
+		delete(fMgr.MaintenanceMap, name)
+	} else {
+		fMgr.MaintenanceMap[name] = window
+		fMgr.armMaintenanceEndTimer(name, window)
+	}
+	fMgr.MaintenanceRWMutex.Unlock()
+	if exist && oldWindow.isActive(fMgr.Clock.Now()) {
+		fMgr.promoteMaintenanceFaults(oldWindow)
+	}
+}
+
+func (fMgr *FaultManager) CreateMaintenanceWindow(config *objects.MaintenanceWindow) (bool, error) {
+	window, err := fMgr.validateMaintenanceWindow(config)
+	if err != nil {
+		return false, err
+	}
+	fMgr.MaintenanceRWMutex.RLock()
+	_, exist := fMgr.MaintenanceMap[config.Name]
+	fMgr.MaintenanceRWMutex.RUnlock()
+	if exist {
+		return false, errors.New(fmt.Sprintln("Maintenance window already configured:", config.Name))
+	}
+	fMgr.setMaintenanceWindow(config.Name, window)
+	return true, nil
+}
+
+func (fMgr *FaultManager) UpdateMaintenanceWindow(oldCfg, newCfg *objects.MaintenanceWindow, attrset []bool) (bool, error) {
+	window, err := fMgr.validateMaintenanceWindow(newCfg)
+	if err != nil {
+		return false, err
+	}
+	fMgr.MaintenanceRWMutex.RLock()
+	_, exist := fMgr.MaintenanceMap[newCfg.Name]
+	fMgr.MaintenanceRWMutex.RUnlock()
+	if !exist {
+		return false, errors.New(fmt.Sprintln("Maintenance window not configured:", newCfg.Name))
+	}
+	fMgr.setMaintenanceWindow(newCfg.Name, window)
+	return true, nil
+}
+
+func (fMgr *FaultManager) DeleteMaintenanceWindow(config *objects.MaintenanceWindow) (bool, error) {
+	fMgr.MaintenanceRWMutex.RLock()
+	_, exist := fMgr.MaintenanceMap[config.Name]
+	fMgr.MaintenanceRWMutex.RUnlock()
+	if !exist {
+		return false, errors.New(fmt.Sprintln("Maintenance window not configured:", config.Name))
+	}
+	fMgr.setMaintenanceWindow(config.Name, nil)
+	return true, nil
+}
@@ -0,0 +1,180 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"sort"
+	"time"
+	"utils/eventUtils"
+)
+
+func getEventName(fEnt FaultDetail) string {
+	return fmt.Sprintf("%s:%s", fEnt.FaultOwnerName, fEnt.FaultEventName)
+}
+
+// isSameFaultEvent returns false if the faults raised for the old definition
+// can no longer be related to the new one
+func isSameFaultEvent(oldEnt, newEnt FaultDetail) bool {
+	return oldEnt.FaultOwnerName == newEnt.FaultOwnerName &&
+		oldEnt.FaultEventName == newEnt.FaultEventName &&
+		oldEnt.FaultSrcObjName == newEnt.FaultSrcObjName
+}
+
+func isFaultEventModified(oldEnt, newEnt FaultDetail) bool {
+	return oldEnt.ClearingEventId != newEnt.ClearingEventId ||
+		oldEnt.ClearingDaemonId != newEnt.ClearingDaemonId ||
+		oldEnt.AlarmSeverity != newEnt.AlarmSeverity
+}
+
+// removeFaultEvent clears the faults and alarms of an event which is no
+// longer defined along with the configuration tied to it. It has to be
+// called before the event maps are replaced so that the clearing is
+// published with the old event details.
+func (fMgr *FaultManager) removeFaultEvent(evtKey EventKey) {
+	fMgr.ClearExistingFaults(evtKey, "", EVENTREMOVED)
+	fMgr.ClearExistingAlarms(evtKey, "", EVENTREMOVED)
+	fMgr.ShelveRWMutex.Lock()
+	for _, sDataEnt := range fMgr.ShelveMap[evtKey] {
+		if sDataEnt.ExpiryTimer != nil {
+			sDataEnt.ExpiryTimer.Stop()
+		}
+	}
+	delete(fMgr.ShelveMap, evtKey)
+	fMgr.ShelveRWMutex.Unlock()
+	fMgr.CfgRWMutex.Lock()
+	delete(fMgr.EventHoldTimeMap, evtKey)
+	delete(fMgr.EscalationPolicyMap, evtKey)
+	fMgr.CfgRWMutex.Unlock()
+}
+
+// applyEventMaps replaces the event maps with the ones built out of reloaded
+// events.json. Faults of unchanged events are preserved along with their
+// FaultEnable state. Caller is expected to have paused the event processing.
+func (fMgr *FaultManager) applyEventMaps(evtMaps *EventMaps) (added, removed, modified []string) {
+	var removedKeys []EventKey
+	fMgr.EvtMapRWMutex.RLock()
+	for evtKey, oldEnt := range fMgr.FaultEventMap {
+		newEnt, exist := evtMaps.FaultEventMap[evtKey]
+		if !exist || !isSameFaultEvent(oldEnt, newEnt) {
+			removed = append(removed, getEventName(oldEnt))
+			removedKeys = append(removedKeys, evtKey)
+			continue
+		}
+		if isFaultEventModified(oldEnt, newEnt) {
+			modified = append(modified, getEventName(newEnt))
+		}
+	}
+	for evtKey, newEnt := range evtMaps.FaultEventMap {
+		oldEnt, exist := fMgr.FaultEventMap[evtKey]
+		if !exist || !isSameFaultEvent(oldEnt, newEnt) {
+			added = append(added, getEventName(newEnt))
+		}
+	}
+	fMgr.EvtMapRWMutex.RUnlock()
+
+	// Clearing looks up the event maps, hence done without holding them
+	for _, evtKey := range removedKeys {
+		fMgr.removeFaultEvent(evtKey)
+	}
+
+	fMgr.EvtMapRWMutex.Lock()
+	for evtKey, newEnt := range evtMaps.FaultEventMap {
+		oldEnt, exist := fMgr.FaultEventMap[evtKey]
+		if exist && isSameFaultEvent(oldEnt, newEnt) {
+			newEnt.RaiseFault = oldEnt.RaiseFault
+			evtMaps.FaultEventMap[evtKey] = newEnt
+		}
+	}
+	fMgr.FaultEventMap = evtMaps.FaultEventMap
+	fMgr.NonFaultEventMap = evtMaps.NonFaultEventMap
+	fMgr.OwnerEventNameMap = evtMaps.OwnerEventNameMap
+	fMgr.DaemonNameMap = evtMaps.DaemonNameMap
+	fMgr.DaemonList = evtMaps.DaemonList
+	fMgr.EvtMapRWMutex.Unlock()
+
+	// Correlation rules are resolved against the event maps
+	err := fMgr.loadCorrelationRules(CORRELATION_RULES_FILE)
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Error Loading Correlation Rules:", err))
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(modified)
+	return added, removed, modified
+}
+
+// ReloadEvents re-parses events.json and applies the changes without losing
+// the faults and alarms of unchanged events. Nothing is applied when problems
+// are found in the event definitions.
+func (fMgr *FaultManager) ReloadEvents() error {
+	fMgr.ReloadMutex.Lock()
+	defer fMgr.ReloadMutex.Unlock()
+	fMgr.ReloadState.LastReloadTime = time.Now().String()
+	evtJson, err := eventUtils.ParseEventsJson()
+	if err != nil {
+		fMgr.logger.Err(fmt.Sprintln("Error Parsing the events.json", err))
+		fMgr.ReloadState.Status = fmt.Sprintln("Failed:", err)
+		return err
+	}
+	evtMaps, errList := LoadEventMaps(evtJson)
+	if len(errList) != 0 {
+		for _, err := range errList {
+			fMgr.logger.Err(fmt.Sprintln(err))
+		}
+		err = errors.New(fmt.Sprintf("%d problems found in event definitions, events.json is not reloaded", len(errList)))
+		fMgr.ReloadState.Status = fmt.Sprintln("Failed:", err)
+		return err
+	}
+
+	fMgr.PauseEventProcessCh <- true
+	<-fMgr.PauseEventProcessAckCh
+	added, removed, modified := fMgr.applyEventMaps(evtMaps)
+	fMgr.PauseEventProcessCh <- true
+
+	fMgr.logger.Info(fmt.Sprintln("Reloaded events.json, added events:", added, "removed events:", removed, "modified events:", modified))
+	fMgr.ReloadState.Status = "Success"
+	fMgr.ReloadState.AddedEvents = added
+	fMgr.ReloadState.RemovedEvents = removed
+	fMgr.ReloadState.ModifiedEvents = modified
+	return nil
+}
+
+func (fMgr *FaultManager) EventsReloadAction(config *objects.EventsReload) (bool, error) {
+	err := fMgr.ReloadEvents()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) GetEventsReloadState(vrf string) (*objects.EventsReloadState, error) {
+	fMgr.ReloadMutex.Lock()
+	defer fMgr.ReloadMutex.Unlock()
+	state := fMgr.ReloadState
+	state.Vrf = vrf
+	return &state, nil
+}
@@ -0,0 +1,119 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	REPEAT_PUBLISH_INTERVAL = time.Duration(30) * time.Second // Min interval between republishing a re-asserted fault
+)
+
+func getOccurrenceCount(count uint32) int32 {
+	if count == 0 {
+		// Entries recorded before occurrences were tracked
+		return 1
+	}
+	return int32(count)
+}
+
+func getLastSeenTime(lastSeenTime, occuranceTime time.Time) string {
+	if lastSeenTime.IsZero() {
+		return occuranceTime.String()
+	}
+	return lastSeenTime.String()
+}
+
+// recordRepeatFault bumps the occurrence count of the existing fault and its
+// alarm when the fault is reported again. Caller is expected to hold
+// FMapRWMutex.
+func (fMgr *FaultManager) recordRepeatFault(evtKey EventKey, fObjKey FaultObjKey, lastSeenTime time.Time) {
+	fDataEnt, exist := fMgr.FaultMap[evtKey][fObjKey]
+	if !exist {
+		return
+	}
+	fMgr.FRBRWMutex.Lock()
+	fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+	fault := fIntf.(FaultRBEntry)
+	if fault.FaultSeqNumber != fDataEnt.FaultSeqNumber {
+		fMgr.FRBRWMutex.Unlock()
+		return
+	}
+	fault.OccurrenceCount = uint32(getOccurrenceCount(fault.OccurrenceCount)) + 1
+	fault.LastSeenTime = lastSeenTime
+	fMgr.FaultRB.UpdateEntryInRingBuffer(fault, fDataEnt.FaultListIdx)
+	fMgr.History.RecordFault(fault)
+	fMgr.FRBRWMutex.Unlock()
+
+	fMgr.AMapRWMutex.RLock()
+	if aDataEnt, exist := fMgr.AlarmMap[evtKey][fObjKey]; exist {
+		fMgr.ARBRWMutex.Lock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		alarm := aIntf.(AlarmRBEntry)
+		if alarm.AlarmSeqNumber == aDataEnt.AlarmSeqNumber && !alarm.Resolved {
+			alarm.OccurrenceCount = uint32(getOccurrenceCount(alarm.OccurrenceCount)) + 1
+			alarm.LastSeenTime = lastSeenTime
+			fMgr.AlarmRB.UpdateEntryInRingBuffer(alarm, aDataEnt.AlarmListIdx)
+			fMgr.History.RecordAlarm(alarm)
+		}
+		fMgr.ARBRWMutex.Unlock()
+	}
+	fMgr.AMapRWMutex.RUnlock()
+
+	if fDataEnt.RepublishTimer != nil {
+		// Update is already scheduled to be published
+		return
+	}
+	delay := REPEAT_PUBLISH_INTERVAL - fMgr.Clock.Now().Sub(fDataEnt.LastPublishTime)
+	if delay <= 0 {
+		fMgr.publishRepeatFault(evtKey, fObjKey, &fDataEnt)
+	} else {
+		fDataEnt.RepublishTimer = fMgr.Clock.AfterFunc(delay, func() {
+			fMgr.FMapRWMutex.Lock()
+			defer fMgr.FMapRWMutex.Unlock()
+			fDataEnt, exist := fMgr.FaultMap[evtKey][fObjKey]
+			if !exist {
+				return
+			}
+			fDataEnt.RepublishTimer = nil
+			fMgr.publishRepeatFault(evtKey, fObjKey, &fDataEnt)
+			fMgr.FaultMap[evtKey][fObjKey] = fDataEnt
+		})
+	}
+	fMgr.FaultMap[evtKey][fObjKey] = fDataEnt
+}
+
+// Caller is expected to hold FMapRWMutex
+func (fMgr *FaultManager) publishRepeatFault(evtKey EventKey, fObjKey FaultObjKey, fDataEnt *FaultData) {
+	fMgr.logger.Debug(fmt.Sprintln("Publishing re-asserted fault", evtKey, fObjKey))
+	fDataEnt.LastPublishTime = fMgr.Clock.Now()
+	fMgr.PublishFaults(fDataEnt.FaultListIdx, TRANSITION_NONE)
+	fMgr.AMapRWMutex.RLock()
+	if aDataEnt, exist := fMgr.AlarmMap[evtKey][fObjKey]; exist {
+		fMgr.PublishAlarms(aDataEnt.AlarmListIdx, TRANSITION_NONE)
+	}
+	fMgr.AMapRWMutex.RUnlock()
+}
@@ -0,0 +1,260 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"utils/ringBuffer"
+)
+
+const (
+	RETENTION_CHECK_INTERVAL = time.Duration(60) * time.Second
+	RB_PURGE_PERCENT         = 10 // Share of the capacity freed when the ring buffer is full
+)
+
+type rbPurgeList struct {
+	keep   []bool
+	kept   int
+	active int
+}
+
+// getRBPurgeList decides which ring buffer entries, listed oldest first, to
+// keep. Entries referenced by the fault/alarm maps are always kept, expired
+// entries are dropped, and then the oldest of the remaining ones till there
+// is room left for the given number of entries within the capacity.
+func getRBPurgeList(length, capacity, room int, entryInfo func(int) (active, expired bool)) rbPurgeList {
+	pList := rbPurgeList{
+		keep: make([]bool, length),
+	}
+	for idx := 0; idx < length; idx++ {
+		active, expired := entryInfo(idx)
+		if active {
+			pList.active++
+		}
+		pList.keep[idx] = active || !expired
+		if pList.keep[idx] {
+			pList.kept++
+		}
+	}
+	for idx := 0; idx < length && pList.kept > capacity-room; idx++ {
+		if active, _ := entryInfo(idx); pList.keep[idx] && !active {
+			pList.keep[idx] = false
+			pList.kept--
+		}
+	}
+	return pList
+}
+
+func isRBEntryExpired(now time.Time, resolved bool, resolutionTime time.Time, retention time.Duration) bool {
+	return resolved && retention > 0 && now.Sub(resolutionTime) > retention
+}
+
+func (fMgr *FaultManager) getHistoryRetention() time.Duration {
+	fMgr.CfgRWMutex.RLock()
+	defer fMgr.CfgRWMutex.RUnlock()
+	return fMgr.HistoryRetention
+}
+
+// purgeFaultRB rebuilds the fault ring buffer with the given capacity and
+// remaps FaultListIdx of the active faults to their new slots. The ring
+// buffer is grown past the capacity rather than dropping an active fault.
+// Caller is expected to hold FMapRWMutex and FRBRWMutex.
+func (fMgr *FaultManager) purgeFaultRB(capacity, room int, retention time.Duration) int {
+	activeSeq := make(map[uint64]bool)
+	for _, fDataMapEnt := range fMgr.FaultMap {
+		for _, fDataEnt := range fDataMapEnt {
+			activeSeq[fDataEnt.FaultSeqNumber] = true
+		}
+	}
+	now := fMgr.Clock.Now()
+	fList := fMgr.FaultRB.GetListOfEntriesFromRingBuffer()
+	pList := getRBPurgeList(len(fList), capacity, room, func(idx int) (bool, bool) {
+		fault := fList[idx].(FaultRBEntry)
+		return activeSeq[fault.FaultSeqNumber], isRBEntryExpired(now, fault.Resolved, fault.ResolutionTime, retention)
+	})
+	if pList.kept == len(fList) && capacity == fMgr.FaultRBCapacity && pList.kept+room <= capacity {
+		return 0
+	}
+	size := capacity
+	if pList.active+room > size {
+		size = pList.active + room
+		fMgr.logger.Err(fmt.Sprintln("Fault history is full of active faults, growing it to", size, "entries"))
+	}
+	faultRB := new(ringBuffer.RingBuffer)
+	faultRB.SetRingBufferCapacity(size)
+	newIdx := make(map[uint64]int)
+	for idx, fIntf := range fList {
+		if pList.keep[idx] {
+			newIdx[fIntf.(FaultRBEntry).FaultSeqNumber], _ = faultRB.InsertIntoRingBuffer(fIntf)
+		}
+	}
+	for _, fDataMapEnt := range fMgr.FaultMap {
+		for fObjKey, fDataEnt := range fDataMapEnt {
+			if idx, exist := newIdx[fDataEnt.FaultSeqNumber]; exist {
+				fDataEnt.FaultListIdx = idx
+				fDataMapEnt[fObjKey] = fDataEnt
+			}
+		}
+	}
+	fMgr.FaultRB = faultRB
+	fMgr.FaultRBCapacity = capacity
+	fMgr.FaultRBCount = pList.kept
+	return len(fList) - pList.kept
+}
+
+// purgeAlarmRB is the alarm counterpart of purgeFaultRB.
+// Caller is expected to hold AMapRWMutex and ARBRWMutex.
+func (fMgr *FaultManager) purgeAlarmRB(capacity, room int, retention time.Duration) int {
+	activeSeq := make(map[uint64]bool)
+	for _, aDataMapEnt := range fMgr.AlarmMap {
+		for _, aDataEnt := range aDataMapEnt {
+			activeSeq[aDataEnt.AlarmSeqNumber] = true
+		}
+	}
+	now := fMgr.Clock.Now()
+	aList := fMgr.AlarmRB.GetListOfEntriesFromRingBuffer()
+	pList := getRBPurgeList(len(aList), capacity, room, func(idx int) (bool, bool) {
+		alarm := aList[idx].(AlarmRBEntry)
+		return activeSeq[alarm.AlarmSeqNumber], isRBEntryExpired(now, alarm.Resolved, alarm.ResolutionTime, retention)
+	})
+	if pList.kept == len(aList) && capacity == fMgr.AlarmRBCapacity && pList.kept+room <= capacity {
+		return 0
+	}
+	size := capacity
+	if pList.active+room > size {
+		size = pList.active + room
+		fMgr.logger.Err(fmt.Sprintln("Alarm history is full of active alarms, growing it to", size, "entries"))
+	}
+	alarmRB := new(ringBuffer.RingBuffer)
+	alarmRB.SetRingBufferCapacity(size)
+	newIdx := make(map[uint64]int)
+	for idx, aIntf := range aList {
+		if pList.keep[idx] {
+			newIdx[aIntf.(AlarmRBEntry).AlarmSeqNumber], _ = alarmRB.InsertIntoRingBuffer(aIntf)
+		}
+	}
+	for _, aDataMapEnt := range fMgr.AlarmMap {
+		for fObjKey, aDataEnt := range aDataMapEnt {
+			if idx, exist := newIdx[aDataEnt.AlarmSeqNumber]; exist {
+				aDataEnt.AlarmListIdx = idx
+				aDataMapEnt[fObjKey] = aDataEnt
+			}
+		}
+	}
+	fMgr.AlarmRB = alarmRB
+	fMgr.AlarmRBCapacity = capacity
+	fMgr.AlarmRBCount = pList.kept
+	return len(aList) - pList.kept
+}
+
+// makeRoomInFaultRB frees a share of the fault ring buffer when it is full
+// so that inserting does not overwrite an active fault.
+// Caller is expected to hold FMapRWMutex and FRBRWMutex.
+func (fMgr *FaultManager) makeRoomInFaultRB(retention time.Duration) {
+	if fMgr.FaultRBCount < fMgr.FaultRBCapacity {
+		return
+	}
+	room := fMgr.FaultRBCapacity*RB_PURGE_PERCENT/100 + 1
+	fMgr.purgeFaultRB(fMgr.FaultRBCapacity, room, retention)
+}
+
+// Caller is expected to hold AMapRWMutex and ARBRWMutex.
+func (fMgr *FaultManager) makeRoomInAlarmRB(retention time.Duration) {
+	if fMgr.AlarmRBCount < fMgr.AlarmRBCapacity {
+		return
+	}
+	room := fMgr.AlarmRBCapacity*RB_PURGE_PERCENT/100 + 1
+	fMgr.purgeAlarmRB(fMgr.AlarmRBCapacity, room, retention)
+}
+
+func (fMgr *FaultManager) countActiveEntries() (faults, alarms int) {
+	for _, fDataMapEnt := range fMgr.FaultMap {
+		faults += len(fDataMapEnt)
+	}
+	for _, aDataMapEnt := range fMgr.AlarmMap {
+		alarms += len(aDataMapEnt)
+	}
+	return faults, alarms
+}
+
+// setHistoryCapacity resizes the ring buffers, refusing a capacity which
+// can not hold the active faults and alarms.
+func (fMgr *FaultManager) setHistoryCapacity(faultCapacity, alarmCapacity int) error {
+	retention := fMgr.getHistoryRetention()
+	fMgr.FMapRWMutex.Lock()
+	defer fMgr.FMapRWMutex.Unlock()
+	fMgr.AMapRWMutex.Lock()
+	defer fMgr.AMapRWMutex.Unlock()
+	activeFaults, activeAlarms := fMgr.countActiveEntries()
+	if faultCapacity < activeFaults {
+		return errors.New(fmt.Sprintln("FaultHistorySize can not be less than the number of active faults:", activeFaults))
+	}
+	if alarmCapacity < activeAlarms {
+		return errors.New(fmt.Sprintln("AlarmHistorySize can not be less than the number of active alarms:", activeAlarms))
+	}
+	fMgr.FRBRWMutex.Lock()
+	fMgr.purgeFaultRB(faultCapacity, 0, retention)
+	fMgr.FRBRWMutex.Unlock()
+	fMgr.ARBRWMutex.Lock()
+	fMgr.purgeAlarmRB(alarmCapacity, 0, retention)
+	fMgr.ARBRWMutex.Unlock()
+	fMgr.History.SetMaxRecords(2 * (faultCapacity + alarmCapacity))
+	fMgr.History.Compact()
+	return nil
+}
+
+// purgeExpiredHistory drops the resolved faults and alarms older than the
+// configured retention.
+func (fMgr *FaultManager) purgeExpiredHistory() {
+	retention := fMgr.getHistoryRetention()
+	if retention == 0 {
+		return
+	}
+	fMgr.FMapRWMutex.Lock()
+	fMgr.FRBRWMutex.Lock()
+	numFaults := fMgr.purgeFaultRB(fMgr.FaultRBCapacity, 0, retention)
+	fMgr.FRBRWMutex.Unlock()
+	fMgr.FMapRWMutex.Unlock()
+	fMgr.AMapRWMutex.Lock()
+	fMgr.ARBRWMutex.Lock()
+	numAlarms := fMgr.purgeAlarmRB(fMgr.AlarmRBCapacity, 0, retention)
+	fMgr.ARBRWMutex.Unlock()
+	fMgr.AMapRWMutex.Unlock()
+	if numFaults != 0 || numAlarms != 0 {
+		fMgr.logger.Info(fmt.Sprintln("Purged", numFaults, "faults and", numAlarms, "alarms older than", retention))
+		fMgr.History.Compact()
+	}
+}
+
+func (fMgr *FaultManager) HistoryRetentionPurger() {
+	ticker := time.NewTicker(RETENTION_CHECK_INTERVAL)
+	for {
+		select {
+		case _ = <-ticker.C:
+			fMgr.purgeExpiredHistory()
+		}
+	}
+}
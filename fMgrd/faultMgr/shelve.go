@@ -0,0 +1,186 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"strings"
+	"time"
+)
+
+func (fMgr *FaultManager) isAlarmShelved(evtKey EventKey, uuid string) bool {
+	fMgr.ShelveRWMutex.RLock()
+	defer fMgr.ShelveRWMutex.RUnlock()
+	sDataMapEnt, exist := fMgr.ShelveMap[evtKey]
+	if !exist {
+		return false
+	}
+	if _, exist := sDataMapEnt[""]; exist {
+		return true
+	}
+	_, exist = sDataMapEnt[uuid]
+	return exist
+}
+
+func (fMgr *FaultManager) AlarmShelveAction(config *objects.AlarmShelve) (retVal bool, err error) {
+	if config.Duration < 0 {
+		return false, errors.New("Invalid shelve duration")
+	}
+	fMgr.PauseEventProcessCh <- true
+	<-fMgr.PauseEventProcessAckCh
+	duration := time.Duration(config.Duration) * time.Second
+	if strings.ToLower(config.EventName) == objects.ALL_EVENTS {
+		for _, evtKey := range fMgr.getOwnerFaultEventKeys(config.OwnerName) {
+			fMgr.shelveAlarms(evtKey, config.SrcObjUUID, duration)
+			retVal = true
+		}
+		if retVal == false {
+			err = errors.New("Unable to find any faulty event for the owner")
+		}
+	} else {
+		evtKeyStr := EventKeyStr{
+			OwnerName: config.OwnerName,
+			EventName: config.EventName,
+		}
+		evtKey, exist := fMgr.getEventKey(evtKeyStr)
+		if !exist {
+			err = errors.New("Unable to find the corresponding event")
+		} else if _, exist := fMgr.getFaultEvent(evtKey); !exist {
+			err = errors.New("Unable to find the corresponding faulty event")
+		} else {
+			fMgr.shelveAlarms(evtKey, config.SrcObjUUID, duration)
+			retVal = true
+		}
+	}
+	fMgr.PauseEventProcessCh <- true
+	return retVal, err
+}
+
+// shelveAlarms withholds alarm generation for the given event and object
+// for duration. A zero duration unshelves the alarms right away.
+func (fMgr *FaultManager) shelveAlarms(evtKey EventKey, uuid string, duration time.Duration) {
+	fMgr.ShelveRWMutex.Lock()
+	sDataMapEnt, exist := fMgr.ShelveMap[evtKey]
+	if !exist {
+		sDataMapEnt = make(map[string]ShelveData)
+		fMgr.ShelveMap[evtKey] = sDataMapEnt
+	}
+	if sDataEnt, exist := sDataMapEnt[uuid]; exist {
+		sDataEnt.ExpiryTimer.Stop()
+		delete(sDataMapEnt, uuid)
+	}
+	if duration == 0 {
+		if len(sDataMapEnt) == 0 {
+			delete(fMgr.ShelveMap, evtKey)
+		}
+		fMgr.ShelveRWMutex.Unlock()
+		fMgr.logger.Info(fmt.Sprintln("Alarms unshelved for:", evtKey, uuid))
+		fMgr.reevaluatePendingAlarms(evtKey, uuid)
+		return
+	}
+	var sDataEnt ShelveData
+	sDataEnt.ExpiryTime = fMgr.Clock.Now().Add(duration)
+	expiryTime := sDataEnt.ExpiryTime
+	sDataEnt.ExpiryTimer = fMgr.Clock.AfterFunc(duration, func() {
+		fMgr.unshelveAlarms(evtKey, uuid, expiryTime)
+	})
+	sDataMapEnt[uuid] = sDataEnt
+	fMgr.ShelveRWMutex.Unlock()
+	fMgr.logger.Info(fmt.Sprintln("Alarms shelved for:", evtKey, uuid, "till", sDataEnt.ExpiryTime))
+}
+
+// unshelveAlarms is called when the shelve expires. The expiry time tells
+// apart the shelve the timer was armed for from one installed afterwards
+// while the timer was waiting for ShelveRWMutex.
+func (fMgr *FaultManager) unshelveAlarms(evtKey EventKey, uuid string, expiryTime time.Time) {
+	fMgr.ShelveRWMutex.Lock()
+	sDataEnt, exist := fMgr.ShelveMap[evtKey][uuid]
+	if !exist || !sDataEnt.ExpiryTime.Equal(expiryTime) {
+		fMgr.ShelveRWMutex.Unlock()
+		return
+	}
+	delete(fMgr.ShelveMap[evtKey], uuid)
+	if len(fMgr.ShelveMap[evtKey]) == 0 {
+		delete(fMgr.ShelveMap, evtKey)
+	}
+	fMgr.ShelveRWMutex.Unlock()
+	fMgr.logger.Info(fmt.Sprintln("Alarm shelve expired for:", evtKey, uuid))
+	fMgr.reevaluatePendingAlarms(evtKey, uuid)
+}
+
+// reevaluatePendingAlarms restarts the alarm timer of every existing fault
+// matching evtKey and uuid which does not have an alarm yet, so that alarms
+// withheld in the meantime get raised once the fault has been standing for
+// the fault to alarm transition time. The active alarms, whose updates were
+// withheld, are published again.
+func (fMgr *FaultManager) reevaluatePendingAlarms(evtKey EventKey, uuid string) {
+	var alarmList []AlarmRBEntry
+	fMgr.FMapRWMutex.Lock()
+	fMgr.AMapRWMutex.RLock()
+	aDataMapEnt, _ := fMgr.AlarmMap[evtKey]
+	for _, aDataEnt := range aDataMapEnt {
+		fMgr.ARBRWMutex.RLock()
+		aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+		fMgr.ARBRWMutex.RUnlock()
+		alarm := aIntf.(AlarmRBEntry)
+		if alarm.AlarmSeqNumber != aDataEnt.AlarmSeqNumber || alarm.Resolved {
+			continue
+		}
+		if uuid == "" || uuid == alarm.SrcObjUUID {
+			alarmList = append(alarmList, alarm)
+		}
+	}
+	fDataMapEnt, _ := fMgr.FaultMap[evtKey]
+	for fObjKey, fDataEnt := range fDataMapEnt {
+		if _, exist := aDataMapEnt[fObjKey]; exist {
+			continue
+		}
+		fMgr.FRBRWMutex.RLock()
+		fIntf := fMgr.FaultRB.GetEntryFromRingBuffer(fDataEnt.FaultListIdx)
+		fMgr.FRBRWMutex.RUnlock()
+		fault := fIntf.(FaultRBEntry)
+		if fault.FaultSeqNumber != fDataEnt.FaultSeqNumber {
+			continue
+		}
+		if uuid != "" && uuid != fault.SrcObjUUID {
+			continue
+		}
+		if fDataEnt.CreateAlarmTimer != nil {
+			fDataEnt.CreateAlarmTimer.Stop()
+		}
+		delay := fMgr.getFaultToAlarmTransitionTime(evtKey) - fMgr.Clock.Now().Sub(fault.OccuranceTime)
+		if delay < 0 {
+			delay = 0
+		}
+		fDataEnt.CreateAlarmTimer = fMgr.startAlarmTimerWithDelay(delay, evtKey, fObjKey, fault.SrcObjKey, fault.SrcObjUUID, fault.SrcObjAttrs, fault.Description, fault.Injected)
+		fDataMapEnt[fObjKey] = fDataEnt
+	}
+	fMgr.AMapRWMutex.RUnlock()
+	fMgr.FMapRWMutex.Unlock()
+	for _, alarm := range alarmList {
+		fMgr.publishAlarmEntry(alarm, TRANSITION_NONE)
+	}
+}
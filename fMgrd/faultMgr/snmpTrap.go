@@ -0,0 +1,344 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"github.com/soniah/gosnmp"
+	"infra/fMgrd/objects"
+	"strings"
+	"sync"
+	"time"
+	"utils/logging"
+)
+
+const (
+	SNMP_TRAP_PORT          = 162
+	SNMP_TRAP_TIMEOUT       = time.Duration(2) * time.Second
+	SNMP_TRAP_RETRIES       = 2
+	SNMP_TRAP_QUEUE_SIZE    = 1000
+	SNMP_VERSION_2C         = "v2c"
+	SNMP_VERSION_3          = "v3"
+	SNMP_SYS_UPTIME_OID     = ".1.3.6.1.2.1.1.3.0"
+	SNMP_TRAP_OID           = ".1.3.6.1.6.3.1.1.4.1.0"
+	ALARM_ACTIVE_STATE_OID  = ".1.3.6.1.2.1.118.0.2" // ALARM-MIB alarmActiveState
+	ALARM_CLEAR_STATE_OID   = ".1.3.6.1.2.1.118.0.3" // ALARM-MIB alarmClearState
+	FMGR_ALARM_VARBIND_BASE = ".1.3.6.1.4.1.52142.1.1.1"
+)
+
+// Varbinds carried in the alarm notifications, appended to FMGR_ALARM_VARBIND_BASE
+const (
+	ALARM_OWNER_NAME_VARBIND = iota + 1
+	ALARM_EVENT_NAME_VARBIND
+	ALARM_SEVERITY_VARBIND
+	ALARM_SRC_OBJ_NAME_VARBIND
+	ALARM_SRC_OBJ_KEY_VARBIND
+	ALARM_SRC_OBJ_UUID_VARBIND
+	ALARM_DESCRIPTION_VARBIND
+	ALARM_OCCURANCE_TIME_VARBIND
+	ALARM_RESOLUTION_TIME_VARBIND
+	ALARM_RESOLUTION_REASON_VARBIND
+)
+
+type trapReceiver struct {
+	config  objects.SnmpTrapReceiver
+	mutex   sync.Mutex // Serializes the use of snmpHdl
+	closed  bool
+	snmpHdl *gosnmp.GoSNMP
+}
+
+type SnmpTrapExporter struct {
+	logger    logging.LoggerIntf
+	rwMutex   sync.RWMutex
+	receivers map[string]*trapReceiver
+	trapCh    chan objects.AlarmState
+	startTime time.Time
+}
+
+func NewSnmpTrapExporter(logger logging.LoggerIntf) *SnmpTrapExporter {
+	exporter := &SnmpTrapExporter{
+		logger:    logger,
+		receivers: make(map[string]*trapReceiver),
+		trapCh:    make(chan objects.AlarmState, SNMP_TRAP_QUEUE_SIZE),
+		startTime: time.Now(),
+	}
+	go exporter.trapSender()
+	return exporter
+}
+
+// ExportAlarm queues the alarm notification, it never blocks the caller
+// which is holding the alarm database locks.
+func (exporter *SnmpTrapExporter) ExportAlarm(aObj objects.AlarmState) {
+	select {
+	case exporter.trapCh <- aObj:
+	default:
+		exporter.logger.Err(fmt.Sprintln("SNMP trap queue is full, dropping notification for alarm:", aObj.OwnerName, aObj.EventName, aObj.SrcObjKey))
+	}
+}
+
+func (exporter *SnmpTrapExporter) getReceivers() []*trapReceiver {
+	exporter.rwMutex.RLock()
+	defer exporter.rwMutex.RUnlock()
+	receivers := make([]*trapReceiver, 0, len(exporter.receivers))
+	for _, receiver := range exporter.receivers {
+		receivers = append(receivers, receiver)
+	}
+	return receivers
+}
+
+// trapSender sends the notifications without holding rwMutex, a slow or
+// unreachable receiver only delays the notifications behind it.
+func (exporter *SnmpTrapExporter) trapSender() {
+	for aObj := range exporter.trapCh {
+		receivers := exporter.getReceivers()
+		if len(receivers) == 0 {
+			continue
+		}
+		trap := exporter.buildAlarmTrap(aObj)
+		for _, receiver := range receivers {
+			err := receiver.sendTrap(trap)
+			if err != nil {
+				exporter.logger.Err(fmt.Sprintln("Error sending SNMP notification to", receiver.config.IpAddr, err))
+			}
+		}
+	}
+}
+
+func getVarbindOid(varbind int) string {
+	return fmt.Sprintf("%s.%d", FMGR_ALARM_VARBIND_BASE, varbind)
+}
+
+func (exporter *SnmpTrapExporter) buildAlarmTrap(aObj objects.AlarmState) gosnmp.SnmpTrap {
+	trapOid := ALARM_ACTIVE_STATE_OID
+	if aObj.ResolutionTime != "N/A" {
+		trapOid = ALARM_CLEAR_STATE_OID
+	}
+	upTime := uint32(time.Since(exporter.startTime) / (10 * time.Millisecond))
+	varbinds := []gosnmp.SnmpPDU{
+		{Name: SNMP_SYS_UPTIME_OID, Type: gosnmp.TimeTicks, Value: upTime},
+		{Name: SNMP_TRAP_OID, Type: gosnmp.ObjectIdentifier, Value: trapOid},
+		{Name: getVarbindOid(ALARM_OWNER_NAME_VARBIND), Type: gosnmp.OctetString, Value: aObj.OwnerName},
+		{Name: getVarbindOid(ALARM_EVENT_NAME_VARBIND), Type: gosnmp.OctetString, Value: aObj.EventName},
+		{Name: getVarbindOid(ALARM_SEVERITY_VARBIND), Type: gosnmp.OctetString, Value: aObj.Severity},
+		{Name: getVarbindOid(ALARM_SRC_OBJ_NAME_VARBIND), Type: gosnmp.OctetString, Value: aObj.SrcObjName},
+		{Name: getVarbindOid(ALARM_SRC_OBJ_KEY_VARBIND), Type: gosnmp.OctetString, Value: aObj.SrcObjKey},
+		{Name: getVarbindOid(ALARM_SRC_OBJ_UUID_VARBIND), Type: gosnmp.OctetString, Value: aObj.SrcObjUUID},
+		{Name: getVarbindOid(ALARM_DESCRIPTION_VARBIND), Type: gosnmp.OctetString, Value: aObj.Description},
+		{Name: getVarbindOid(ALARM_OCCURANCE_TIME_VARBIND), Type: gosnmp.OctetString, Value: aObj.OccuranceTime},
+	}
+	if trapOid == ALARM_CLEAR_STATE_OID {
+		varbinds = append(varbinds,
+			gosnmp.SnmpPDU{Name: getVarbindOid(ALARM_RESOLUTION_TIME_VARBIND), Type: gosnmp.OctetString, Value: aObj.ResolutionTime},
+			gosnmp.SnmpPDU{Name: getVarbindOid(ALARM_RESOLUTION_REASON_VARBIND), Type: gosnmp.OctetString, Value: aObj.ResolutionReason})
+	}
+	return gosnmp.SnmpTrap{
+		Variables: varbinds,
+	}
+}
+
+func (receiver *trapReceiver) sendTrap(trap gosnmp.SnmpTrap) error {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	if receiver.closed {
+		// Receiver got updated or deleted while the notification was queued
+		return nil
+	}
+	if receiver.snmpHdl == nil {
+		snmpHdl := newSnmpHandle(receiver.config)
+		err := snmpHdl.Connect()
+		if err != nil {
+			return err
+		}
+		receiver.snmpHdl = snmpHdl
+	}
+	trap.IsInform = receiver.config.Inform
+	_, err := receiver.snmpHdl.SendTrap(trap)
+	if err != nil {
+		// Reconnect on the next notification
+		receiver.snmpHdl.Conn.Close()
+		receiver.snmpHdl = nil
+	}
+	return err
+}
+
+func (receiver *trapReceiver) close() {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	receiver.closed = true
+	if receiver.snmpHdl != nil {
+		receiver.snmpHdl.Conn.Close()
+		receiver.snmpHdl = nil
+	}
+}
+
+func newSnmpHandle(config objects.SnmpTrapReceiver) *gosnmp.GoSNMP {
+	snmpHdl := &gosnmp.GoSNMP{
+		Target:    config.IpAddr,
+		Port:      uint16(config.Port),
+		Transport: "udp",
+		Community: config.Community,
+		Version:   gosnmp.Version2c,
+		Timeout:   SNMP_TRAP_TIMEOUT,
+		Retries:   SNMP_TRAP_RETRIES,
+	}
+	if snmpHdl.Port == 0 {
+		snmpHdl.Port = SNMP_TRAP_PORT
+	}
+	if strings.ToLower(config.Version) != SNMP_VERSION_3 {
+		return snmpHdl
+	}
+	usmParams := &gosnmp.UsmSecurityParameters{
+		UserName:                 config.SecurityName,
+		AuthenticationProtocol:   gosnmp.NoAuth,
+		AuthenticationPassphrase: config.AuthPassword,
+		PrivacyProtocol:          gosnmp.NoPriv,
+		PrivacyPassphrase:        config.PrivPassword,
+	}
+	snmpHdl.MsgFlags = gosnmp.NoAuthNoPriv
+	switch strings.ToUpper(config.AuthProtocol) {
+	case "MD5":
+		usmParams.AuthenticationProtocol = gosnmp.MD5
+		snmpHdl.MsgFlags = gosnmp.AuthNoPriv
+	case "SHA":
+		usmParams.AuthenticationProtocol = gosnmp.SHA
+		snmpHdl.MsgFlags = gosnmp.AuthNoPriv
+	}
+	switch strings.ToUpper(config.PrivProtocol) {
+	case "DES":
+		usmParams.PrivacyProtocol = gosnmp.DES
+		snmpHdl.MsgFlags = gosnmp.AuthPriv
+	case "AES":
+		usmParams.PrivacyProtocol = gosnmp.AES
+		snmpHdl.MsgFlags = gosnmp.AuthPriv
+	}
+	snmpHdl.Version = gosnmp.Version3
+	snmpHdl.SecurityModel = gosnmp.UserSecurityModel
+	snmpHdl.SecurityParameters = usmParams
+	return snmpHdl
+}
+
+func validateSnmpTrapReceiver(config *objects.SnmpTrapReceiver) error {
+	if config.IpAddr == "" {
+		return errors.New("IpAddr of SNMP trap receiver should be provided")
+	}
+	if config.Port < 0 || config.Port > 65535 {
+		return errors.New("Invalid Port value provided")
+	}
+	switch strings.ToLower(config.Version) {
+	case SNMP_VERSION_2C:
+		if config.Community == "" {
+			return errors.New("Community should be provided for SNMP v2c trap receiver")
+		}
+	case SNMP_VERSION_3:
+		if config.SecurityName == "" {
+			return errors.New("SecurityName should be provided for SNMP v3 trap receiver")
+		}
+		authProto := strings.ToUpper(config.AuthProtocol)
+		privProto := strings.ToUpper(config.PrivProtocol)
+		if authProto != "" && authProto != "MD5" && authProto != "SHA" {
+			return errors.New("Invalid AuthProtocol value provided, supported values are MD5 and SHA")
+		}
+		if privProto != "" && privProto != "DES" && privProto != "AES" {
+			return errors.New("Invalid PrivProtocol value provided, supported values are DES and AES")
+		}
+		if privProto != "" && authProto == "" {
+			return errors.New("PrivProtocol can not be used without AuthProtocol")
+		}
+	default:
+		return errors.New("Invalid Version value provided, supported values are v2c and v3")
+	}
+	return nil
+}
+
+func (exporter *SnmpTrapExporter) addReceiver(config *objects.SnmpTrapReceiver) error {
+	exporter.rwMutex.Lock()
+	defer exporter.rwMutex.Unlock()
+	if _, exist := exporter.receivers[config.IpAddr]; exist {
+		return errors.New(fmt.Sprintln("SNMP trap receiver already configured:", config.IpAddr))
+	}
+	exporter.receivers[config.IpAddr] = &trapReceiver{
+		config: *config,
+	}
+	return nil
+}
+
+func (exporter *SnmpTrapExporter) updateReceiver(config *objects.SnmpTrapReceiver) error {
+	exporter.rwMutex.Lock()
+	defer exporter.rwMutex.Unlock()
+	receiver, exist := exporter.receivers[config.IpAddr]
+	if !exist {
+		return errors.New(fmt.Sprintln("SNMP trap receiver not configured:", config.IpAddr))
+	}
+	exporter.receivers[config.IpAddr] = &trapReceiver{
+		config: *config,
+	}
+	// Closing waits for an inflight notification, do not hold up the caller
+	go receiver.close()
+	return nil
+}
+
+func (exporter *SnmpTrapExporter) deleteReceiver(ipAddr string) error {
+	exporter.rwMutex.Lock()
+	defer exporter.rwMutex.Unlock()
+	receiver, exist := exporter.receivers[ipAddr]
+	if !exist {
+		return errors.New(fmt.Sprintln("SNMP trap receiver not configured:", ipAddr))
+	}
+	delete(exporter.receivers, ipAddr)
+	go receiver.close()
+	return nil
+}
+
+func (fMgr *FaultManager) CreateSnmpTrapReceiver(config *objects.SnmpTrapReceiver) (bool, error) {
+	err := validateSnmpTrapReceiver(config)
+	if err != nil {
+		return false, err
+	}
+	err = fMgr.SnmpTrapExp.addReceiver(config)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) UpdateSnmpTrapReceiver(oldCfg, newCfg *objects.SnmpTrapReceiver, attrset []bool) (bool, error) {
+	err := validateSnmpTrapReceiver(newCfg)
+	if err != nil {
+		return false, err
+	}
+	err = fMgr.SnmpTrapExp.updateReceiver(newCfg)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) DeleteSnmpTrapReceiver(config *objects.SnmpTrapReceiver) (bool, error) {
+	err := fMgr.SnmpTrapExp.deleteReceiver(config.IpAddr)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
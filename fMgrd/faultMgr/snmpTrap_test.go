@@ -0,0 +1,183 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"fmt"
+	"infra/fMgrd/objects"
+	"net"
+	"testing"
+	"time"
+	"utils/logging"
+)
+
+// berVarbinds walks the BER encoded SNMP message and returns the varbinds
+// keyed by their OID. Only the types used in the alarm notifications are
+// decoded.
+func berVarbinds(buf []byte, varbinds map[string]string) error {
+	for len(buf) > 0 {
+		if len(buf) < 2 {
+			return fmt.Errorf("truncated BER element")
+		}
+		tag := buf[0]
+		length := int(buf[1])
+		hdrLen := 2
+		if length&0x80 != 0 {
+			numBytes := length & 0x7f
+			if len(buf) < 2+numBytes {
+				return fmt.Errorf("truncated BER length")
+			}
+			length = 0
+			for _, b := range buf[2 : 2+numBytes] {
+				length = length<<8 | int(b)
+			}
+			hdrLen += numBytes
+		}
+		if len(buf) < hdrLen+length {
+			return fmt.Errorf("truncated BER value")
+		}
+		val := buf[hdrLen : hdrLen+length]
+		buf = buf[hdrLen+length:]
+		if tag != 0x30 && (tag < 0xa0 || tag > 0xa8) {
+			continue
+		}
+		// A varbind is a sequence of the OID and its value
+		if tag == 0x30 && len(val) > 2 && val[0] == 0x06 && int(val[1]) < len(val)-2 {
+			oidLen := int(val[1])
+			name := berOid(val[2 : 2+oidLen])
+			value := val[2+oidLen:]
+			if len(value) >= 2 {
+				switch value[0] {
+				case 0x04:
+					varbinds[name] = string(value[2:])
+				case 0x06:
+					varbinds[name] = berOid(value[2:])
+				}
+			}
+			continue
+		}
+		if err := berVarbinds(val, varbinds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func berOid(buf []byte) string {
+	if len(buf) == 0 {
+		return ""
+	}
+	oid := fmt.Sprintf(".%d.%d", buf[0]/40, buf[0]%40)
+	val := 0
+	for _, b := range buf[1:] {
+		val = val<<7 | int(b&0x7f)
+		if b&0x80 == 0 {
+			oid += fmt.Sprintf(".%d", val)
+			val = 0
+		}
+	}
+	return oid
+}
+
+func receiveTrap(t *testing.T, conn net.PacketConn) map[string]string {
+	buf := make([]byte, 65535)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal("Error receiving SNMP notification:", err)
+	}
+	varbinds := make(map[string]string)
+	if err := berVarbinds(buf[:n], varbinds); err != nil {
+		t.Fatal("Error decoding SNMP notification:", err)
+	}
+	return varbinds
+}
+
+func checkVarbind(t *testing.T, varbinds map[string]string, oid, expected string) {
+	if varbinds[oid] != expected {
+		t.Errorf("Varbind %s: expected %q, got %q", oid, expected, varbinds[oid])
+	}
+}
+
+func TestSnmpTrapReceiver(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Error starting UDP trap receiver:", err)
+	}
+	defer conn.Close()
+	logger, err := logging.NewLogger("fMgrd", "FMGRD", false)
+	if err != nil {
+		t.Fatal("Error creating logger:", err)
+	}
+	fMgr := &FaultManager{
+		logger:      logger,
+		SnmpTrapExp: NewSnmpTrapExporter(logger),
+	}
+	fMgr.RegisterAlarmExporter(fMgr.SnmpTrapExp)
+	_, err = fMgr.CreateSnmpTrapReceiver(&objects.SnmpTrapReceiver{
+		IpAddr:    "127.0.0.1",
+		Port:      int32(conn.LocalAddr().(*net.UDPAddr).Port),
+		Version:   SNMP_VERSION_2C,
+		Community: "public",
+	})
+	if err != nil {
+		t.Fatal("Error creating SNMP trap receiver:", err)
+	}
+
+	aObj := objects.AlarmState{
+		OwnerName:        "asicd",
+		EventName:        "PortOperStateDown",
+		Severity:         "Major",
+		SrcObjName:       "Port",
+		SrcObjKey:        "IntfRef:fpPort1",
+		SrcObjUUID:       "00000000-0000-0000-0000-000000000001",
+		Description:      "Port Operstate Down",
+		OccuranceTime:    "2016-10-18 10:00:00 +0000 UTC",
+		ResolutionTime:   "N/A",
+		ResolutionReason: "N/A",
+	}
+	// A refresh of the alarm state is not exported
+	fMgr.exportAlarm(aObj, TRANSITION_NONE)
+	fMgr.exportAlarm(aObj, TRANSITION_RAISED)
+	varbinds := receiveTrap(t, conn)
+	checkVarbind(t, varbinds, SNMP_TRAP_OID, ALARM_ACTIVE_STATE_OID)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_OWNER_NAME_VARBIND), aObj.OwnerName)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_EVENT_NAME_VARBIND), aObj.EventName)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_SEVERITY_VARBIND), aObj.Severity)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_SRC_OBJ_KEY_VARBIND), aObj.SrcObjKey)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_SRC_OBJ_UUID_VARBIND), aObj.SrcObjUUID)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_OCCURANCE_TIME_VARBIND), aObj.OccuranceTime)
+	if _, exist := varbinds[getVarbindOid(ALARM_RESOLUTION_TIME_VARBIND)]; exist {
+		t.Error("Active alarm notification carries the resolution time")
+	}
+
+	aObj.ResolutionTime = "2016-10-18 10:05:00 +0000 UTC"
+	aObj.ResolutionReason = getResolutionReason(AUTOCLEARED)
+	fMgr.exportAlarm(aObj, TRANSITION_CLEARED)
+	varbinds = receiveTrap(t, conn)
+	checkVarbind(t, varbinds, SNMP_TRAP_OID, ALARM_CLEAR_STATE_OID)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_OWNER_NAME_VARBIND), aObj.OwnerName)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_RESOLUTION_TIME_VARBIND), aObj.ResolutionTime)
+	checkVarbind(t, varbinds, getVarbindOid(ALARM_RESOLUTION_REASON_VARBIND), aObj.ResolutionReason)
+}
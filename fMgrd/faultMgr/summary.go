@@ -0,0 +1,171 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"encoding/json"
+	"infra/fMgrd/objects"
+	"sort"
+)
+
+const (
+	ALARM_SUMMARY_CHANNEL = "fMgrdAlarmSummary"
+	OTHER_SEVERITY        = "Other"
+)
+
+// AlarmSummary keeps the count of active alarms, it is maintained
+// incrementally as alarms are raised, escalated and cleared. Suppressed
+// alarms are counted once they are published as independent alarms.
+type AlarmSummary struct {
+	SeverityCount map[string]int
+	OwnerCount    map[string]int
+	TotalCount    int
+}
+
+func getSummarySeverity(severity string) string {
+	level := getSeverityLevel(severity)
+	if level == -1 {
+		return OTHER_SEVERITY
+	}
+	return severityLevels[level]
+}
+
+func (fMgr *FaultManager) getAlarmSummaryKeys(alarm *AlarmRBEntry) (severity, owner string) {
+	evtKey := EventKey{
+		DaemonId: alarm.OwnerId,
+		EventId:  alarm.EventId,
+	}
+	fEnt, _ := fMgr.getFaultEvent(evtKey)
+	return getSummarySeverity(getAlarmSeverity(alarm, fEnt)), fEnt.FaultOwnerName
+}
+
+// updateAlarmSummary accounts for a raised alarm with delta 1 and a cleared
+// one with delta -1
+func (fMgr *FaultManager) updateAlarmSummary(alarm *AlarmRBEntry, delta int) {
+	if alarm.Suppressed {
+		return
+	}
+	severity, owner := fMgr.getAlarmSummaryKeys(alarm)
+	fMgr.SummaryMutex.Lock()
+	fMgr.Summary.SeverityCount[severity] += delta
+	if fMgr.Summary.SeverityCount[severity] <= 0 {
+		delete(fMgr.Summary.SeverityCount, severity)
+	}
+	fMgr.Summary.OwnerCount[owner] += delta
+	if fMgr.Summary.OwnerCount[owner] <= 0 {
+		delete(fMgr.Summary.OwnerCount, owner)
+	}
+	fMgr.Summary.TotalCount += delta
+	summary := fMgr.getAlarmSummaryObject()
+	fMgr.SummaryMutex.Unlock()
+	fMgr.publishAlarmSummary(summary)
+}
+
+func (fMgr *FaultManager) changeAlarmSummarySeverity(oldSeverity, newSeverity string) {
+	oldSeverity = getSummarySeverity(oldSeverity)
+	newSeverity = getSummarySeverity(newSeverity)
+	if oldSeverity == newSeverity {
+		return
+	}
+	fMgr.SummaryMutex.Lock()
+	if fMgr.Summary.SeverityCount[oldSeverity] > 0 {
+		fMgr.Summary.SeverityCount[oldSeverity]--
+		if fMgr.Summary.SeverityCount[oldSeverity] == 0 {
+			delete(fMgr.Summary.SeverityCount, oldSeverity)
+		}
+	}
+	fMgr.Summary.SeverityCount[newSeverity]++
+	summary := fMgr.getAlarmSummaryObject()
+	fMgr.SummaryMutex.Unlock()
+	fMgr.publishAlarmSummary(summary)
+}
+
+// rebuildAlarmSummary recomputes the summary out of the alarm database.
+// Caller is expected to hold AMapRWMutex.
+func (fMgr *FaultManager) rebuildAlarmSummary() {
+	summary := AlarmSummary{
+		SeverityCount: make(map[string]int),
+		OwnerCount:    make(map[string]int),
+	}
+	fMgr.ARBRWMutex.RLock()
+	for _, aDataMapEnt := range fMgr.AlarmMap {
+		for _, aDataEnt := range aDataMapEnt {
+			aIntf := fMgr.AlarmRB.GetEntryFromRingBuffer(aDataEnt.AlarmListIdx)
+			alarm := aIntf.(AlarmRBEntry)
+			if alarm.AlarmSeqNumber != aDataEnt.AlarmSeqNumber || alarm.Resolved || alarm.Suppressed {
+				continue
+			}
+			severity, owner := fMgr.getAlarmSummaryKeys(&alarm)
+			summary.SeverityCount[severity]++
+			summary.OwnerCount[owner]++
+			summary.TotalCount++
+		}
+	}
+	fMgr.ARBRWMutex.RUnlock()
+	fMgr.SummaryMutex.Lock()
+	fMgr.Summary = summary
+	fMgr.SummaryMutex.Unlock()
+}
+
+// Caller is expected to hold SummaryMutex
+func (fMgr *FaultManager) getAlarmSummaryObject() objects.AlarmSummary {
+	obj := objects.AlarmSummary{
+		TotalCount:      int32(fMgr.Summary.TotalCount),
+		CriticalCount:   int32(fMgr.Summary.SeverityCount["Critical"]),
+		MajorCount:      int32(fMgr.Summary.SeverityCount["Major"]),
+		MinorCount:      int32(fMgr.Summary.SeverityCount["Minor"]),
+		WarningCount:    int32(fMgr.Summary.SeverityCount["Warning"]),
+		OtherCount:      int32(fMgr.Summary.SeverityCount[OTHER_SEVERITY]),
+		HighestSeverity: "None",
+	}
+	for level := len(severityLevels) - 1; level >= 0; level-- {
+		if fMgr.Summary.SeverityCount[severityLevels[level]] > 0 {
+			obj.HighestSeverity = severityLevels[level]
+			break
+		}
+	}
+	for owner, count := range fMgr.Summary.OwnerCount {
+		obj.OwnerSummary = append(obj.OwnerSummary, objects.AlarmOwnerCount{
+			OwnerName: owner,
+			Count:     int32(count),
+		})
+	}
+	sort.Slice(obj.OwnerSummary, func(i, j int) bool {
+		return obj.OwnerSummary[i].OwnerName < obj.OwnerSummary[j].OwnerName
+	})
+	return obj
+}
+
+func (fMgr *FaultManager) publishAlarmSummary(summary objects.AlarmSummary) {
+	msg, _ := json.Marshal(summary)
+	fMgr.AlarmPubHdl.Publish(ALARM_SUMMARY_CHANNEL, msg)
+}
+
+func (fMgr *FaultManager) GetAlarmSummary(vrf string) (*objects.AlarmSummary, error) {
+	fMgr.SummaryMutex.Lock()
+	summary := fMgr.getAlarmSummaryObject()
+	fMgr.SummaryMutex.Unlock()
+	summary.Vrf = vrf
+	return &summary, nil
+}
@@ -0,0 +1,474 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"infra/fMgrd/objects"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"utils/logging"
+)
+
+const (
+	SYSLOG_UDP_PORT         = 514
+	SYSLOG_TCP_PORT         = 514
+	SYSLOG_TLS_PORT         = 6514
+	SYSLOG_QUEUE_SIZE       = 1000 // Max pending messages per syslog server
+	SYSLOG_DIAL_TIMEOUT     = time.Duration(5) * time.Second
+	SYSLOG_WRITE_TIMEOUT    = time.Duration(5) * time.Second
+	SYSLOG_MIN_RETRY_DELAY  = time.Duration(1) * time.Second
+	SYSLOG_MAX_RETRY_DELAY  = time.Duration(30) * time.Second
+	SYSLOG_APP_NAME         = "fMgrd"
+	SYSLOG_SD_ID            = "fMgrd@52142"
+	SYSLOG_TIMESTAMP_FORMAT = "2006-01-02T15:04:05.000000Z07:00"
+	SYSLOG_TRANSPORT_UDP    = "udp"
+	SYSLOG_TRANSPORT_TCP    = "tcp"
+	SYSLOG_TRANSPORT_TLS    = "tls"
+)
+
+// Syslog severities as per RFC 5424
+const (
+	SYSLOG_SEV_ALERT    = 1
+	SYSLOG_SEV_CRITICAL = 2
+	SYSLOG_SEV_ERROR    = 3
+	SYSLOG_SEV_WARNING  = 4
+	SYSLOG_SEV_NOTICE   = 5
+	SYSLOG_SEV_INFO     = 6
+)
+
+var syslogFacilityMap = map[string]int{
+	"local0": 16,
+	"local1": 17,
+	"local2": 18,
+	"local3": 19,
+	"local4": 20,
+	"local5": 21,
+	"local6": 22,
+	"local7": 23,
+}
+
+// getSyslogSeverity maps the alarm severity to the syslog severity as per
+// RFC 5674, indeterminate and cleared map to notice.
+func getSyslogSeverity(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return SYSLOG_SEV_ALERT
+	case "major":
+		return SYSLOG_SEV_CRITICAL
+	case "minor":
+		return SYSLOG_SEV_ERROR
+	case "warning":
+		return SYSLOG_SEV_WARNING
+	}
+	return SYSLOG_SEV_NOTICE
+}
+
+// escapeSDParam escapes the characters which are not allowed as is in a
+// structured data param value
+func escapeSDParam(val string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(val)
+}
+
+type syslogMsg struct {
+	severity int
+	msgId    string
+	sdParams [][2]string
+	msg      string
+}
+
+type syslogServer struct {
+	config    objects.SyslogServer
+	facility  int
+	logger    logging.LoggerIntf
+	mutex     sync.Mutex
+	queue     []string
+	dropCount uint64
+	notifyCh  chan bool
+	stopCh    chan bool
+	doneCh    chan bool // Closed once the sender is done with the queue
+	conn      net.Conn
+}
+
+type SyslogExporter struct {
+	logger   logging.LoggerIntf
+	rwMutex  sync.RWMutex
+	servers  map[string]*syslogServer
+	hostname string
+	procId   string
+	now      func() time.Time
+}
+
+func NewSyslogExporter(logger logging.LoggerIntf) *SyslogExporter {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogExporter{
+		logger:   logger,
+		servers:  make(map[string]*syslogServer),
+		hostname: hostname,
+		procId:   strconv.Itoa(os.Getpid()),
+		now:      time.Now,
+	}
+}
+
+func (exporter *SyslogExporter) ExportFault(fObj objects.FaultState) {
+	sMsg := syslogMsg{
+		severity: SYSLOG_SEV_NOTICE,
+		msgId:    "FAULT_RAISED",
+		msg:      fObj.Description,
+	}
+	if fObj.ResolutionTime != "N/A" {
+		sMsg.severity = SYSLOG_SEV_INFO
+		sMsg.msgId = "FAULT_CLEARED"
+	}
+	sMsg.sdParams = [][2]string{
+		{"owner", fObj.OwnerName},
+		{"event", fObj.EventName},
+		{"srcObjName", fObj.SrcObjName},
+		{"srcObjKey", fObj.SrcObjKey},
+		{"uuid", fObj.SrcObjUUID},
+		{"occuranceTime", fObj.OccuranceTime},
+	}
+	if fObj.ResolutionTime != "N/A" {
+		sMsg.sdParams = append(sMsg.sdParams, [2]string{"resolutionReason", fObj.ResolutionReason})
+	}
+	exporter.export(sMsg)
+}
+
+func (exporter *SyslogExporter) ExportAlarm(aObj objects.AlarmState) {
+	sMsg := syslogMsg{
+		severity: getSyslogSeverity(aObj.Severity),
+		msgId:    "ALARM_RAISED",
+		msg:      aObj.Description,
+	}
+	if aObj.ResolutionTime != "N/A" {
+		sMsg.severity = SYSLOG_SEV_NOTICE
+		sMsg.msgId = "ALARM_CLEARED"
+	}
+	sMsg.sdParams = [][2]string{
+		{"owner", aObj.OwnerName},
+		{"event", aObj.EventName},
+		{"severity", aObj.Severity},
+		{"srcObjName", aObj.SrcObjName},
+		{"srcObjKey", aObj.SrcObjKey},
+		{"uuid", aObj.SrcObjUUID},
+		{"occuranceTime", aObj.OccuranceTime},
+	}
+	if aObj.ResolutionTime != "N/A" {
+		sMsg.sdParams = append(sMsg.sdParams, [2]string{"resolutionReason", aObj.ResolutionReason})
+	}
+	exporter.export(sMsg)
+}
+
+// export formats the message for each of the syslog servers and queues it,
+// it never blocks on the network.
+func (exporter *SyslogExporter) export(sMsg syslogMsg) {
+	exporter.rwMutex.RLock()
+	defer exporter.rwMutex.RUnlock()
+	for _, server := range exporter.servers {
+		server.enqueue(exporter.formatMsg(server.facility, sMsg))
+	}
+}
+
+// formatMsg builds the RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID PARAM="VALUE"...] MSG
+func (exporter *SyslogExporter) formatMsg(facility int, sMsg syslogMsg) string {
+	sd := "[" + SYSLOG_SD_ID
+	for _, param := range sMsg.sdParams {
+		sd += fmt.Sprintf(" %s=\"%s\"", param[0], escapeSDParam(param[1]))
+	}
+	sd += "]"
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s", facility*8+sMsg.severity,
+		exporter.now().Format(SYSLOG_TIMESTAMP_FORMAT), exporter.hostname, SYSLOG_APP_NAME,
+		exporter.procId, sMsg.msgId, sd, sMsg.msg)
+}
+
+func newSyslogServer(logger logging.LoggerIntf, config *objects.SyslogServer) *syslogServer {
+	facility, exist := syslogFacilityMap[strings.ToLower(config.Facility)]
+	if !exist {
+		facility = syslogFacilityMap["local0"]
+	}
+	server := &syslogServer{
+		config:   *config,
+		facility: facility,
+		logger:   logger,
+		notifyCh: make(chan bool, 1),
+		stopCh:   make(chan bool),
+		doneCh:   make(chan bool),
+	}
+	return server
+}
+
+// enqueue adds the message to the bounded queue, the oldest message is
+// dropped when the queue is full.
+func (server *syslogServer) enqueue(msg string) {
+	server.mutex.Lock()
+	if len(server.queue) >= SYSLOG_QUEUE_SIZE {
+		server.queue = server.queue[1:]
+		server.dropCount++
+		if server.dropCount%SYSLOG_QUEUE_SIZE == 1 {
+			server.logger.Err(fmt.Sprintln("Syslog queue is full for", server.config.IpAddr, "dropped messages:", server.dropCount))
+		}
+	}
+	server.queue = append(server.queue, msg)
+	server.mutex.Unlock()
+	select {
+	case server.notifyCh <- true:
+	default:
+	}
+}
+
+// sender sends the queued messages in order. Once stopped, the message being
+// sent is either sent or left at the head of the queue before doneCh closes.
+func (server *syslogServer) sender() {
+	defer close(server.doneCh)
+	defer server.closeConn()
+	retryDelay := SYSLOG_MIN_RETRY_DELAY
+	for {
+		select {
+		case <-server.stopCh:
+			return
+		default:
+		}
+		server.mutex.Lock()
+		if len(server.queue) == 0 {
+			server.mutex.Unlock()
+			select {
+			case <-server.notifyCh:
+				continue
+			case <-server.stopCh:
+				return
+			}
+		}
+		msg := server.queue[0]
+		server.mutex.Unlock()
+
+		err := server.send(msg)
+		if err != nil {
+			server.logger.Err(fmt.Sprintln("Error sending syslog message to", server.config.IpAddr, err, "retrying in", retryDelay))
+			server.closeConn()
+			select {
+			case <-time.After(retryDelay):
+			case <-server.stopCh:
+				return
+			}
+			if retryDelay *= 2; retryDelay > SYSLOG_MAX_RETRY_DELAY {
+				retryDelay = SYSLOG_MAX_RETRY_DELAY
+			}
+			continue
+		}
+		retryDelay = SYSLOG_MIN_RETRY_DELAY
+		server.mutex.Lock()
+		// The head could have been dropped by enqueue in the mean time
+		if len(server.queue) > 0 && server.queue[0] == msg {
+			server.queue = server.queue[1:]
+		}
+		server.mutex.Unlock()
+	}
+}
+
+func (server *syslogServer) getPort() int {
+	if server.config.Port != 0 {
+		return int(server.config.Port)
+	}
+	switch strings.ToLower(server.config.Transport) {
+	case SYSLOG_TRANSPORT_TCP:
+		return SYSLOG_TCP_PORT
+	case SYSLOG_TRANSPORT_TLS:
+		return SYSLOG_TLS_PORT
+	}
+	return SYSLOG_UDP_PORT
+}
+
+func (server *syslogServer) connect() (net.Conn, error) {
+	addr := net.JoinHostPort(server.config.IpAddr, strconv.Itoa(server.getPort()))
+	switch strings.ToLower(server.config.Transport) {
+	case SYSLOG_TRANSPORT_TCP:
+		return net.DialTimeout("tcp", addr, SYSLOG_DIAL_TIMEOUT)
+	case SYSLOG_TRANSPORT_TLS:
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: server.config.TLSSkipVerify,
+		}
+		if server.config.CACertFile != "" {
+			caCert, err := ioutil.ReadFile(server.config.CACertFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+				return nil, errors.New(fmt.Sprintln("Unable to parse CA certificate from", server.config.CACertFile))
+			}
+		}
+		dialer := &net.Dialer{
+			Timeout: SYSLOG_DIAL_TIMEOUT,
+		}
+		return tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	}
+	return net.Dial("udp", addr)
+}
+
+func (server *syslogServer) send(msg string) error {
+	if server.conn == nil {
+		conn, err := server.connect()
+		if err != nil {
+			return err
+		}
+		server.conn = conn
+	}
+	server.conn.SetWriteDeadline(time.Now().Add(SYSLOG_WRITE_TIMEOUT))
+	_, err := server.conn.Write([]byte(frameMsg(server.config.Transport, msg)))
+	return err
+}
+
+// frameMsg frames the message for the transport, stream transports use octet
+// counting framing (RFC 6587/RFC 5425).
+func frameMsg(transport, msg string) string {
+	if strings.ToLower(transport) == SYSLOG_TRANSPORT_UDP {
+		return msg
+	}
+	return fmt.Sprintf("%d %s", len(msg), msg)
+}
+
+func (server *syslogServer) closeConn() {
+	if server.conn != nil {
+		server.conn.Close()
+		server.conn = nil
+	}
+}
+
+func validateSyslogServer(config *objects.SyslogServer) error {
+	if net.ParseIP(config.IpAddr) == nil {
+		return errors.New("Invalid IpAddr value provided")
+	}
+	if config.Port < 0 || config.Port > 65535 {
+		return errors.New("Invalid Port value provided")
+	}
+	switch strings.ToLower(config.Transport) {
+	case SYSLOG_TRANSPORT_UDP, SYSLOG_TRANSPORT_TCP, SYSLOG_TRANSPORT_TLS:
+	default:
+		return errors.New("Invalid Transport value provided, supported values are udp, tcp and tls")
+	}
+	if config.Facility != "" {
+		if _, exist := syslogFacilityMap[strings.ToLower(config.Facility)]; !exist {
+			return errors.New("Invalid Facility value provided, supported values are local0 to local7")
+		}
+	}
+	return nil
+}
+
+func (exporter *SyslogExporter) addServer(config *objects.SyslogServer) error {
+	exporter.rwMutex.Lock()
+	defer exporter.rwMutex.Unlock()
+	if _, exist := exporter.servers[config.IpAddr]; exist {
+		return errors.New(fmt.Sprintln("Syslog server already configured:", config.IpAddr))
+	}
+	server := newSyslogServer(exporter.logger, config)
+	exporter.servers[config.IpAddr] = server
+	go server.sender()
+	return nil
+}
+
+func (exporter *SyslogExporter) deleteServer(ipAddr string) error {
+	exporter.rwMutex.Lock()
+	defer exporter.rwMutex.Unlock()
+	server, exist := exporter.servers[ipAddr]
+	if !exist {
+		return errors.New(fmt.Sprintln("Syslog server not configured:", ipAddr))
+	}
+	close(server.stopCh)
+	delete(exporter.servers, ipAddr)
+	return nil
+}
+
+// updateServer restarts the server with new config, messages pending for the
+// server are carried over. The old sender is waited upon before handing over
+// its queue, so that the message it was sending is not sent twice.
+func (exporter *SyslogExporter) updateServer(config *objects.SyslogServer) error {
+	exporter.rwMutex.Lock()
+	server, exist := exporter.servers[config.IpAddr]
+	if !exist {
+		exporter.rwMutex.Unlock()
+		return errors.New(fmt.Sprintln("Syslog server not configured:", config.IpAddr))
+	}
+	close(server.stopCh)
+	// Messages exported meanwhile are queued behind the handed over ones
+	newServer := newSyslogServer(exporter.logger, config)
+	exporter.servers[config.IpAddr] = newServer
+	exporter.rwMutex.Unlock()
+
+	<-server.doneCh
+	server.mutex.Lock()
+	newServer.mutex.Lock()
+	newServer.queue = append(server.queue, newServer.queue...)
+	if excess := len(newServer.queue) - SYSLOG_QUEUE_SIZE; excess > 0 {
+		newServer.queue = newServer.queue[excess:]
+		newServer.dropCount += uint64(excess)
+	}
+	newServer.mutex.Unlock()
+	server.mutex.Unlock()
+	go newServer.sender()
+	return nil
+}
+
+func (fMgr *FaultManager) CreateSyslogServer(config *objects.SyslogServer) (bool, error) {
+	err := validateSyslogServer(config)
+	if err != nil {
+		return false, err
+	}
+	err = fMgr.SyslogExp.addServer(config)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) UpdateSyslogServer(oldCfg, newCfg *objects.SyslogServer, attrset []bool) (bool, error) {
+	err := validateSyslogServer(newCfg)
+	if err != nil {
+		return false, err
+	}
+	err = fMgr.SyslogExp.updateServer(newCfg)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (fMgr *FaultManager) DeleteSyslogServer(config *objects.SyslogServer) (bool, error) {
+	err := fMgr.SyslogExp.deleteServer(config.IpAddr)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
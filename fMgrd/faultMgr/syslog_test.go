@@ -0,0 +1,233 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"bufio"
+	"infra/fMgrd/objects"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+	"utils/logging"
+)
+
+func newTestSyslogExporter(t *testing.T) *SyslogExporter {
+	logger, err := logging.NewLogger("fMgrd", "FMGRD", false)
+	if err != nil {
+		t.Fatal("Error creating logger:", err)
+	}
+	exporter := NewSyslogExporter(logger)
+	exporter.hostname = "switch1"
+	exporter.procId = "1234"
+	exporter.now = func() time.Time {
+		return time.Date(2016, 10, 18, 10, 0, 0, 123456000, time.UTC)
+	}
+	return exporter
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		expected int
+	}{
+		{"Critical", SYSLOG_SEV_ALERT},
+		{"major", SYSLOG_SEV_CRITICAL},
+		{"Minor", SYSLOG_SEV_ERROR},
+		{"Warning", SYSLOG_SEV_WARNING},
+		{"Indeterminate", SYSLOG_SEV_NOTICE},
+		{"Cleared", SYSLOG_SEV_NOTICE},
+		{"", SYSLOG_SEV_NOTICE},
+	}
+	for _, test := range tests {
+		if sev := getSyslogSeverity(test.severity); sev != test.expected {
+			t.Errorf("Severity %q: expected %d, got %d", test.severity, test.expected, sev)
+		}
+	}
+}
+
+func TestSyslogFormat(t *testing.T) {
+	aObj := objects.AlarmState{
+		OwnerName:        "asicd",
+		EventName:        "PortOperStateDown",
+		Severity:         "Major",
+		SrcObjName:       "Port",
+		SrcObjKey:        `IntfRef:"fpPort1"`,
+		SrcObjUUID:       "00000000-0000-0000-0000-000000000001",
+		Description:      "Port Operstate Down",
+		OccuranceTime:    "2016-10-18 10:00:00 +0000 UTC",
+		ResolutionTime:   "N/A",
+		ResolutionReason: "N/A",
+	}
+	clearedAlarm := aObj
+	clearedAlarm.ResolutionTime = "2016-10-18 10:05:00 +0000 UTC"
+	clearedAlarm.ResolutionReason = "Cleared"
+	fObj := objects.FaultState{
+		OwnerName:        "asicd",
+		EventName:        "PortOperStateDown",
+		SrcObjName:       "Port",
+		SrcObjKey:        `Path:C:\ports[1]`,
+		SrcObjUUID:       "00000000-0000-0000-0000-000000000001",
+		Description:      "Port Operstate Down",
+		OccuranceTime:    "2016-10-18 10:00:00 +0000 UTC",
+		ResolutionTime:   "N/A",
+		ResolutionReason: "N/A",
+	}
+	clearedFault := fObj
+	clearedFault.ResolutionTime = "2016-10-18 10:05:00 +0000 UTC"
+	clearedFault.ResolutionReason = "Cleared"
+
+	header := " 2016-10-18T10:00:00.123456Z switch1 fMgrd 1234 "
+	tests := []struct {
+		name     string
+		facility string
+		export   func(exporter *SyslogExporter)
+		expected string
+	}{
+		{
+			name:     "alarm raised",
+			facility: "local0",
+			export:   func(exporter *SyslogExporter) { exporter.ExportAlarm(aObj) },
+			expected: "<130>1" + header + `ALARM_RAISED [fMgrd@52142 owner="asicd" event="PortOperStateDown" severity="Major" srcObjName="Port" srcObjKey="IntfRef:\"fpPort1\"" uuid="00000000-0000-0000-0000-000000000001" occuranceTime="2016-10-18 10:00:00 +0000 UTC"] Port Operstate Down`,
+		},
+		{
+			name:     "alarm cleared",
+			facility: "local7",
+			export:   func(exporter *SyslogExporter) { exporter.ExportAlarm(clearedAlarm) },
+			expected: "<189>1" + header + `ALARM_CLEARED [fMgrd@52142 owner="asicd" event="PortOperStateDown" severity="Major" srcObjName="Port" srcObjKey="IntfRef:\"fpPort1\"" uuid="00000000-0000-0000-0000-000000000001" occuranceTime="2016-10-18 10:00:00 +0000 UTC" resolutionReason="Cleared"] Port Operstate Down`,
+		},
+		{
+			name:     "fault raised",
+			facility: "",
+			export:   func(exporter *SyslogExporter) { exporter.ExportFault(fObj) },
+			expected: "<133>1" + header + `FAULT_RAISED [fMgrd@52142 owner="asicd" event="PortOperStateDown" srcObjName="Port" srcObjKey="Path:C:\\ports[1\]" uuid="00000000-0000-0000-0000-000000000001" occuranceTime="2016-10-18 10:00:00 +0000 UTC"] Port Operstate Down`,
+		},
+		{
+			name:     "fault cleared",
+			facility: "LOCAL1",
+			export:   func(exporter *SyslogExporter) { exporter.ExportFault(clearedFault) },
+			expected: "<142>1" + header + `FAULT_CLEARED [fMgrd@52142 owner="asicd" event="PortOperStateDown" srcObjName="Port" srcObjKey="Path:C:\\ports[1\]" uuid="00000000-0000-0000-0000-000000000001" occuranceTime="2016-10-18 10:00:00 +0000 UTC" resolutionReason="Cleared"] Port Operstate Down`,
+		},
+	}
+	for _, test := range tests {
+		exporter := newTestSyslogExporter(t)
+		// The sender is not started, the message stays queued
+		server := newSyslogServer(exporter.logger, &objects.SyslogServer{
+			IpAddr:    "127.0.0.1",
+			Transport: SYSLOG_TRANSPORT_UDP,
+			Facility:  test.facility,
+		})
+		exporter.servers["127.0.0.1"] = server
+		test.export(exporter)
+		if len(server.queue) != 1 {
+			t.Errorf("%s: expected 1 queued message, got %d", test.name, len(server.queue))
+			continue
+		}
+		if server.queue[0] != test.expected {
+			t.Errorf("%s:\nexpected %q\ngot      %q", test.name, test.expected, server.queue[0])
+		}
+	}
+}
+
+func TestSyslogFraming(t *testing.T) {
+	msg := "<133>1 - - fMgrd - FAULT_RAISED [fMgrd@52142] Port Operstate Down"
+	tests := []struct {
+		transport string
+		expected  string
+	}{
+		{SYSLOG_TRANSPORT_UDP, msg},
+		{SYSLOG_TRANSPORT_TCP, "65 " + msg},
+		{SYSLOG_TRANSPORT_TLS, "65 " + msg},
+		{"TCP", "65 " + msg},
+	}
+	for _, test := range tests {
+		if framed := frameMsg(test.transport, msg); framed != test.expected {
+			t.Errorf("Transport %s: expected %q, got %q", test.transport, test.expected, framed)
+		}
+	}
+}
+
+// readFrame reads one octet counted syslog frame off the stream
+func readFrame(t *testing.T, conn net.Conn, reader *bufio.Reader) string {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		t.Fatal("Error reading syslog frame length:", err)
+	}
+	msgLen, err := strconv.Atoi(length[:len(length)-1])
+	if err != nil {
+		t.Fatal("Invalid syslog frame length:", length)
+	}
+	msg := make([]byte, msgLen)
+	if _, err = io.ReadFull(reader, msg); err != nil {
+		t.Fatal("Error reading syslog frame:", err)
+	}
+	return string(msg)
+}
+
+func TestSyslogUpdateServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Error starting TCP syslog receiver:", err)
+	}
+	defer listener.Close()
+	config := objects.SyslogServer{
+		IpAddr:    "127.0.0.1",
+		Port:      int32(listener.Addr().(*net.TCPAddr).Port),
+		Transport: SYSLOG_TRANSPORT_TCP,
+	}
+	exporter := newTestSyslogExporter(t)
+	if err = exporter.addServer(&config); err != nil {
+		t.Fatal("Error adding syslog server:", err)
+	}
+	first := syslogMsg{severity: SYSLOG_SEV_NOTICE, msgId: "FIRST", msg: "first"}
+	second := syslogMsg{severity: SYSLOG_SEV_NOTICE, msgId: "SECOND", msg: "second"}
+
+	exporter.export(first)
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal("Error accepting syslog connection:", err)
+	}
+	defer conn.Close()
+	if msg := readFrame(t, conn, bufio.NewReader(conn)); msg != exporter.formatMsg(16, first) {
+		t.Errorf("Expected %q, got %q", exporter.formatMsg(16, first), msg)
+	}
+
+	config.Facility = "local1"
+	if err = exporter.updateServer(&config); err != nil {
+		t.Fatal("Error updating syslog server:", err)
+	}
+	exporter.export(second)
+	newConn, err := listener.Accept()
+	if err != nil {
+		t.Fatal("Error accepting syslog connection:", err)
+	}
+	defer newConn.Close()
+	// The message sent by the old server is not sent again
+	if msg := readFrame(t, newConn, bufio.NewReader(newConn)); msg != exporter.formatMsg(17, second) {
+		t.Errorf("Expected %q, got %q", exporter.formatMsg(17, second), msg)
+	}
+	exporter.deleteServer(config.IpAddr)
+}
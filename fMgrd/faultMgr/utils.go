@@ -38,15 +38,22 @@ func (fMgr *FaultManager) generateFaultObjKey(ownerName, srcObjName string, srcO
 		return "", "", "", errors.New(fmt.Sprintln("Unable to find the ObjKey of", srcObjName, srcObjKey, err))
 	}
 
-	srcObjUUID, err := fMgr.getUUID(srcObjName, dbObjKey)
+	srcObjUUID, err := fMgr.getUUID(ownerName, srcObjName, dbObjKey)
 	if err != nil {
 		fMgr.logger.Err("Unable to find the UUID of", srcObjName, srcObjKey, err)
 		return "", "", "", errors.New(fmt.Sprintln("Unable to find the UUID of", srcObjName, srcObjKey, err))
 	}
-	return FaultObjKey(fmt.Sprintf("%s#%s#%s", srcObjName, objKey, srcObjUUID)), srcObjUUID, objKey, err
+	return buildFaultObjKey(srcObjName, objKey, srcObjUUID), srcObjUUID, objKey, err
 }
 
-func (fMgr *FaultManager) getUUID(srcObjName, dbObjKey string) (uuid string, err error) {
+func buildFaultObjKey(srcObjName, objKey, srcObjUUID string) FaultObjKey {
+	return FaultObjKey(fmt.Sprintf("%s#%s#%s", srcObjName, objKey, srcObjUUID))
+}
+
+func (fMgr *FaultManager) getUUID(ownerName, srcObjName, dbObjKey string) (uuid string, err error) {
+	if isInternalSrcObj(ownerName, srcObjName) {
+		return getInternalObjUUID(dbObjKey), nil
+	}
 	return fMgr.dbHdl.GetUUIDFromObjKey(dbObjKey)
 }
 
@@ -58,14 +65,51 @@ func getResolutionReason(reason Reason) string {
 		return "Cleared because of FaultEnable(Enable=false) Action"
 	case FAULTCLEARED:
 		return "Cleared because of FaultClear Action"
+	case EVENTREMOVED:
+		return "Cleared because the event was removed from events.json"
 	}
 	return "Unknown"
 }
 
 func getEventObjKey(ownerName, srcObjName string, srcObjKey interface{}) (objKey string, dbObjKey string, err error) {
+	if obj, exist := internalEventKeyMap[strings.ToUpper(ownerName)][srcObjName]; exist {
+		bytes, _ := json.Marshal(srcObjKey)
+		return obj.GetObjDBKey(bytes)
+	}
 	objKeyMap, _ := events.EventKeyMap[strings.ToUpper(ownerName)]
-	obj, _ := objKeyMap[srcObjName]
+	obj, exist := objKeyMap[srcObjName]
+	if !exist {
+		return "", "", errors.New(fmt.Sprintln("No event key map entry for", ownerName, srcObjName))
+	}
 	bytes, _ := json.Marshal(srcObjKey)
 
 	return obj.GetObjDBKey(bytes)
 }
+
+// getStateEventKey resolves the fault event for a keyed state lookup. Owner
+// and event names take precedence over ids when both are provided.
+func (fMgr *FaultManager) getStateEventKey(ownerId, eventId int, ownerName, eventName, srcObjName string) (EventKey, error) {
+	evtKey := EventKey{
+		DaemonId: ownerId,
+		EventId:  eventId,
+	}
+	if ownerName != "" && eventName != "" {
+		evtKeyStr := EventKeyStr{
+			OwnerName: ownerName,
+			EventName: eventName,
+		}
+		key, exist := fMgr.getEventKey(evtKeyStr)
+		if !exist {
+			return evtKey, errors.New(fmt.Sprintln("Unable to find the event", ownerName, eventName))
+		}
+		evtKey = key
+	}
+	fEnt, exist := fMgr.getFaultEvent(evtKey)
+	if !exist {
+		return evtKey, errors.New(fmt.Sprintln("Unable to find the faulty event", evtKey))
+	}
+	if srcObjName != "" && srcObjName != fEnt.FaultSrcObjName {
+		return evtKey, errors.New(fmt.Sprintln("Source object", srcObjName, "does not match the faulty event", evtKey))
+	}
+	return evtKey, nil
+}
@@ -0,0 +1,68 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package faultMgr
+
+import (
+	"errors"
+	"fmt"
+	"models/events"
+	"strings"
+	"utils/eventUtils"
+)
+
+// validateEventDefs reports the problems in event definitions which do not
+// prevent building the event maps but degrade fault handling at runtime.
+func validateEventDefs(evtJson *eventUtils.EventJson) (errList []error) {
+	for _, daemon := range evtJson.DaemonEvents {
+		if daemon.DaemonId == DAEMON_STATUS_OWNER_ID && daemon.DaemonName != DAEMON_STATUS_OWNER {
+			errList = append(errList, errors.New(fmt.Sprintln("DaemonId", daemon.DaemonId, "of", daemon.DaemonName, "is reserved for", DAEMON_STATUS_OWNER)))
+		}
+		objKeyMap, exist := events.EventKeyMap[strings.ToUpper(daemon.DaemonName)]
+		for _, evt := range daemon.EventList {
+			if evt.IsFault == true && getSeverityLevel(evt.Fault.AlarmSeverity) == -1 {
+				errList = append(errList, errors.New(fmt.Sprintln("Unknown alarm severity", evt.Fault.AlarmSeverity, "for fault:", daemon.DaemonName, evt.EventName, "supported values are:", severityLevels)))
+			}
+			if isInternalSrcObj(daemon.DaemonName, evt.SrcObjName) {
+				continue
+			}
+			if !exist {
+				errList = append(errList, errors.New(fmt.Sprintln("No event key map found for daemon:", daemon.DaemonName, "event:", evt.EventName)))
+				continue
+			}
+			if _, ok := objKeyMap[evt.SrcObjName]; !ok {
+				errList = append(errList, errors.New(fmt.Sprintln("SrcObjName", evt.SrcObjName, "has no entry in event key map for:", daemon.DaemonName, evt.EventName)))
+			}
+		}
+	}
+	return errList
+}
+
+// LoadEventMaps builds the event maps out of parsed events.json, along with
+// the events defined by fault manager, and reports every problem found in the
+// event definitions.
+func LoadEventMaps(evtJson *eventUtils.EventJson) (*EventMaps, []error) {
+	evtJson = withDaemonStatusEvents(evtJson)
+	evtMaps, errList := BuildEventMaps(evtJson)
+	return evtMaps, append(errList, validateEventDefs(evtJson)...)
+}
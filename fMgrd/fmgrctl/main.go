@@ -0,0 +1,162 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+// fmgrctl is the offline companion tool of fMgrd
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"infra/fMgrd/faultMgr"
+	"infra/fMgrd/scenario"
+	"io/ioutil"
+	"os"
+	"utils/eventUtils"
+	"utils/logging"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: fmgrctl <command> [options]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  validate [-f events.json]    Validate the event definitions used by fMgrd")
+	fmt.Fprintln(os.Stderr, "  scenario [-f scenarios.json] [-e events.json]")
+	fmt.Fprintln(os.Stderr, "                               Run fault manager scenarios on a simulated clock, the builtin ones by default")
+}
+
+func parseEventsJson(fileName string) (*eventUtils.EventJson, error) {
+	if fileName == "" {
+		return eventUtils.ParseEventsJson()
+	}
+	bytes, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var evtJson eventUtils.EventJson
+	err = json.Unmarshal(bytes, &evtJson)
+	if err != nil {
+		return nil, err
+	}
+	return &evtJson, nil
+}
+
+func validate(args []string) int {
+	flagSet := flag.NewFlagSet("validate", flag.ExitOnError)
+	fileName := flagSet.String("f", "", "events.json to be validated, defaults to the one used by fMgrd")
+	flagSet.Parse(args)
+
+	evtJson, err := parseEventsJson(*fileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error parsing events.json:", err)
+		return 2
+	}
+	evtMaps, errList := faultMgr.LoadEventMaps(evtJson)
+	for _, err := range errList {
+		fmt.Print(err)
+	}
+	if len(errList) != 0 {
+		fmt.Println(len(errList), "problems found in event definitions")
+		return 1
+	}
+	fmt.Println("Event definitions are valid:", len(evtMaps.DaemonList), "daemons,", len(evtMaps.FaultEventMap), "faults")
+	return 0
+}
+
+func loadScenarios(fileName string) ([]scenario.Scenario, error) {
+	bytes, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var scenarios []scenario.Scenario
+	err = json.Unmarshal(bytes, &scenarios)
+	if err != nil {
+		return nil, err
+	}
+	return scenarios, nil
+}
+
+func runScenarios(args []string) int {
+	flagSet := flag.NewFlagSet("scenario", flag.ExitOnError)
+	fileName := flagSet.String("f", "", "JSON list of scenarios to be run, defaults to the builtin ones")
+	evtFileName := flagSet.String("e", "", "events.json used by the scenarios which do not define their events")
+	flagSet.Parse(args)
+
+	scenarios := scenario.BuiltinScenarios
+	evtJson := scenario.BuiltinEvents
+	var err error
+	if *fileName != "" {
+		scenarios, err = loadScenarios(*fileName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading scenarios:", err)
+			return 2
+		}
+	}
+	if *evtFileName != "" {
+		evtJson, err = parseEventsJson(*evtFileName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error parsing events.json:", err)
+			return 2
+		}
+	}
+	logger, err := logging.NewLogger("fmgrctl", "FMGRCTL", false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error starting the logger:", err)
+		return 2
+	}
+
+	runner := scenario.NewRunner(logger, evtJson)
+	failed := 0
+	for idx := range scenarios {
+		result := runner.Run(&scenarios[idx])
+		if len(result.Failures) == 0 {
+			fmt.Println("PASS", result.Name, "-", result.Steps, "steps")
+			continue
+		}
+		failed++
+		fmt.Println("FAIL", result.Name)
+		for _, failure := range result.Failures {
+			fmt.Println("   ", failure)
+		}
+	}
+	fmt.Println(len(scenarios)-failed, "of", len(scenarios), "scenarios passed")
+	if failed != 0 {
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "validate":
+		os.Exit(validate(os.Args[2:]))
+	case "scenario":
+		os.Exit(runScenarios(os.Args[2:]))
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
@@ -36,6 +36,19 @@ type AlarmState struct {
 	SrcObjUUID       string
 	ResolutionTime   string
 	ResolutionReason string
+	Acknowledged     bool
+	AckedBy          string
+	AckTime          string
+	AckNote          string
+	Flapping         bool
+	FlapCount        int32
+	EscalationLevel  int32
+	Suppressed       bool
+	SuppressedBy     string
+	OccurrenceCount  int32
+	LastSeenTime     string
+	SeqNumber        int64
+	Injected         bool
 }
 
 type AlarmStateGetInfo struct {
@@ -44,3 +57,19 @@ type AlarmStateGetInfo struct {
 	More   bool
 	List   []AlarmState
 }
+
+type AlarmAck struct {
+	OwnerName  string
+	EventName  string
+	SrcObjUUID string // Required, alarms are acknowledged per source object
+	Ack        bool
+	AckedBy    string
+	Note       string
+}
+
+type AlarmShelve struct {
+	OwnerName  string
+	EventName  string
+	SrcObjUUID string
+	Duration   int32 // In seconds, 0 unshelves the alarms
+}
@@ -35,6 +35,12 @@ type FaultState struct {
 	SrcObjUUID       string
 	ResolutionTime   string
 	ResolutionReason string
+	FlapCount        int32
+	OccurrenceCount  int32
+	LastSeenTime     string
+	SeqNumber        int64
+	Injected         bool
+	InMaintenance    bool
 }
 
 type FaultStateGetInfo struct {
@@ -59,3 +65,10 @@ type FaultClear struct {
 	EventName  string
 	SrcObjUUID string
 }
+
+type FaultInject struct {
+	OwnerName   string
+	EventName   string
+	SrcObjKey   string // JSON encoded key of the source object
+	Description string
+}
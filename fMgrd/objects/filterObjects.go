@@ -0,0 +1,73 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package objects
+
+const (
+	FILTER_ALL        = "all"
+	FILTER_RESOLVED   = "resolved"
+	FILTER_UNRESOLVED = "unresolved"
+)
+
+// StateFilter restricts the fault and alarm bulk queries. Empty fields match
+// everything, times are in RFC3339 format and bound the occurance time.
+type StateFilter struct {
+	OwnerName  string
+	EventName  string
+	Severity   string
+	SrcObjKey  string
+	SrcObjUUID string
+	Resolved   string
+	FromTime   string
+	ToTime     string
+}
+
+const (
+	CURSOR_BACKWARD = "backward"
+	CURSOR_FORWARD  = "forward"
+)
+
+// StateCursor pages through the fault and alarm history by sequence number.
+// Backward returns the entries older than SeqNumber newest first, forward
+// returns the entries newer than SeqNumber oldest first. A negative SeqNumber
+// starts from the newest (backward) or the oldest (forward) entry, so that
+// forward from the last seen sequence number gives an incremental sync.
+type StateCursor struct {
+	SeqNumber int64
+	Direction string
+	Count     int
+}
+
+type FaultStateCursorInfo struct {
+	Count         int
+	More          bool
+	NextSeqNumber int64
+	List          []FaultState
+}
+
+type AlarmStateCursorInfo struct {
+	Count         int
+	More          bool
+	NextSeqNumber int64
+	List          []AlarmState
+}
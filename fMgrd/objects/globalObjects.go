@@ -0,0 +1,163 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package objects
+
+type FMgrGlobal struct {
+	Vrf                        string
+	FaultToAlarmTransitionTime int32 // In seconds, 0 for the default of 3
+	AlarmTransitionTime        int32 // In seconds, 0 for the default of 3
+	FlapWindow                 int32 // In seconds, 0 for the default of 60
+	FlapThreshold              int32 // Transitions within FlapWindow, 0 for the default of 10, -1 disables
+	FlapQuietPeriod            int32 // In seconds, 0 for the default of 60
+	FaultHistorySize           int32 // Max entries in fault history, 0 for the default of 100000
+	AlarmHistorySize           int32 // Max entries in alarm history, 0 for the default of 100000
+	HistoryRetention           int32 // In seconds, resolved entries older than this are purged, 0 disables
+	HistoryExportDir           string
+	EventOverflowPolicy        string // block (default), drop-newest or drop-oldest
+}
+
+type AlarmEscalation struct {
+	OwnerName          string
+	EventName          string
+	EscalationInterval int32 // In seconds
+	MaxSeverity        string
+}
+
+type EventHoldTime struct {
+	OwnerName                  string
+	EventName                  string
+	FaultToAlarmTransitionTime int32 // In seconds
+	AlarmTransitionTime        int32 // In seconds
+}
+
+type SnmpTrapReceiver struct {
+	IpAddr       string
+	Port         int32
+	Version      string // v2c or v3
+	Community    string
+	Inform       bool
+	SecurityName string
+	AuthProtocol string // MD5 or SHA
+	AuthPassword string
+	PrivProtocol string // DES or AES
+	PrivPassword string
+}
+
+type SyslogServer struct {
+	IpAddr        string
+	Port          int32
+	Transport     string // udp, tcp or tls
+	Facility      string // local0 to local7
+	CACertFile    string
+	TLSSkipVerify bool
+}
+
+// MaintenanceWindow withholds the alarms of an owner, or of one of its source
+// objects when SrcObjKey is set, between StartTime and EndTime (RFC3339)
+type MaintenanceWindow struct {
+	Name               string
+	OwnerName          string
+	SrcObjKey          string // JSON as in FaultInject, e.g. {"IntfRef":"fpPort1"}
+	StartTime          string
+	EndTime            string
+	RecurrenceInterval int32 // In seconds, 0 for one time window
+}
+
+type EventsReload struct {
+	Vrf string
+}
+
+type EventsReloadState struct {
+	Vrf            string
+	LastReloadTime string
+	Status         string
+	AddedEvents    []string
+	RemovedEvents  []string
+	ModifiedEvents []string
+}
+
+// HistoryExport writes the fault and alarm history occured between FromTime
+// and ToTime (RFC3339) into FileName under HistoryExportDir. The export runs
+// in the background, HistoryExportState reports its progress.
+type HistoryExport struct {
+	FileName  string
+	Format    string // json or csv
+	OwnerName string
+	FromTime  string
+	ToTime    string
+}
+
+type HistoryExportState struct {
+	FileName   string
+	Path       string
+	Format     string
+	ExportTime string
+	Status     string // InProgress, Success or Failed with the reason
+	FaultCount int32
+	AlarmCount int32
+}
+
+type HistoryExportStateGetInfo struct {
+	EndIdx int
+	Count  int
+	More   bool
+	List   []HistoryExportState
+}
+
+// EventIngestState counts the events received from an owner daemon and the
+// outcome of processing them
+type EventIngestState struct {
+	OwnerName        string
+	Received         int64
+	Processed        int64
+	UnmarshalErrors  int64
+	UnknownEvents    int64
+	EnableSuppressed int64
+	ChannelFull      int64
+	Dropped          int64
+}
+
+type EventIngestStateGetInfo struct {
+	EndIdx int
+	Count  int
+	More   bool
+	List   []EventIngestState
+}
+
+type AlarmOwnerCount struct {
+	OwnerName string
+	Count     int32
+}
+
+type AlarmSummary struct {
+	Vrf             string
+	TotalCount      int32
+	CriticalCount   int32
+	MajorCount      int32
+	MinorCount      int32
+	WarningCount    int32
+	OtherCount      int32
+	HighestSeverity string
+	OwnerSummary    []AlarmOwnerCount
+}
@@ -27,12 +27,13 @@ import (
 	"fMgrd"
 	"fmt"
 	"infra/fMgrd/api"
+	"infra/fMgrd/objects"
 	//"utils/logging"
 )
 
 func (h *rpcServiceHandler) CreateFMgrGlobal(conf *fMgrd.FMgrGlobal) (bool, error) {
-	h.logger.Info(fmt.Sprintln("Received CreateFMgrGlobal call"))
-	return true, nil
+	h.logger.Info(fmt.Sprintln("Received CreateFMgrGlobal call", conf))
+	return api.CreateFMgrGlobal(convertToObjFmtFMgrGlobal(conf))
 }
 
 func (h *rpcServiceHandler) DeleteFMgrGlobal(conf *fMgrd.FMgrGlobal) (bool, error) {
@@ -41,13 +42,23 @@ func (h *rpcServiceHandler) DeleteFMgrGlobal(conf *fMgrd.FMgrGlobal) (bool, erro
 }
 
 func (h *rpcServiceHandler) UpdateFMgrGlobal(origConf *fMgrd.FMgrGlobal, newConf *fMgrd.FMgrGlobal, attrset []bool, op []*fMgrd.PatchOpInfo) (bool, error) {
-	return true, nil
+	h.logger.Info(fmt.Sprintln("Update FMgr config attrs:", origConf, newConf, attrset))
+	return api.UpdateFMgrGlobal(convertToObjFmtFMgrGlobal(origConf), convertToObjFmtFMgrGlobal(newConf), attrset)
 }
 
 func (h *rpcServiceHandler) GetBulkFaultState(fromIndex fMgrd.Int, count fMgrd.Int) (*fMgrd.FaultStateGetInfo, error) {
 	h.logger.Info(fmt.Sprintln("Get bulk call for Faults"))
+	return h.getBulkFaultState(fromIndex, count, nil)
+}
+
+func (h *rpcServiceHandler) GetBulkFaultStateWithFilter(fromIndex fMgrd.Int, count fMgrd.Int, filter *fMgrd.StateFilter) (*fMgrd.FaultStateGetInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get bulk call for Faults with filter", filter))
+	return h.getBulkFaultState(fromIndex, count, convertToObjFmtStateFilter(filter))
+}
+
+func (h *rpcServiceHandler) getBulkFaultState(fromIndex fMgrd.Int, count fMgrd.Int, filter *objects.StateFilter) (*fMgrd.FaultStateGetInfo, error) {
 	var getBulkObj fMgrd.FaultStateGetInfo
-	info, err := api.GetBulkFault(int(fromIndex), int(count))
+	info, err := api.GetBulkFault(int(fromIndex), int(count), filter)
 	if err != nil {
 		return nil, err
 	}
@@ -61,14 +72,28 @@ func (h *rpcServiceHandler) GetBulkFaultState(fromIndex fMgrd.Int, count fMgrd.I
 	return &getBulkObj, err
 }
 
-func (h *rpcServiceHandler) GetFaultState(ownerId int32, eventId int32, ownerName string, eventName string, srcObjName string) (*fMgrd.FaultState, error) {
-	return nil, nil
+func (h *rpcServiceHandler) GetFaultState(ownerId int32, eventId int32, ownerName string, eventName string, srcObjName string, srcObjUUID string) (*fMgrd.FaultState, error) {
+	h.logger.Info(fmt.Sprintln("Get call for Fault", ownerId, eventId, ownerName, eventName, srcObjName, srcObjUUID))
+	obj, err := api.GetFault(int(ownerId), int(eventId), ownerName, eventName, srcObjName, srcObjUUID)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtFaultState(*obj), nil
 }
 
 func (h *rpcServiceHandler) GetBulkAlarmState(fromIndex fMgrd.Int, count fMgrd.Int) (*fMgrd.AlarmStateGetInfo, error) {
 	h.logger.Info(fmt.Sprintln("Get bulk call for Alarm"))
+	return h.getBulkAlarmState(fromIndex, count, nil)
+}
+
+func (h *rpcServiceHandler) GetBulkAlarmStateWithFilter(fromIndex fMgrd.Int, count fMgrd.Int, filter *fMgrd.StateFilter) (*fMgrd.AlarmStateGetInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get bulk call for Alarm with filter", filter))
+	return h.getBulkAlarmState(fromIndex, count, convertToObjFmtStateFilter(filter))
+}
+
+func (h *rpcServiceHandler) getBulkAlarmState(fromIndex fMgrd.Int, count fMgrd.Int, filter *objects.StateFilter) (*fMgrd.AlarmStateGetInfo, error) {
 	var getBulkObj fMgrd.AlarmStateGetInfo
-	info, err := api.GetBulkAlarm(int(fromIndex), int(count))
+	info, err := api.GetBulkAlarm(int(fromIndex), int(count), filter)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +107,97 @@ func (h *rpcServiceHandler) GetBulkAlarmState(fromIndex fMgrd.Int, count fMgrd.I
 	return &getBulkObj, err
 }
 
-func (h *rpcServiceHandler) GetAlarmState(ownerId int32, eventId int32, ownerName string, eventName string, srcObjName string) (*fMgrd.AlarmState, error) {
-	return nil, nil
+func (h *rpcServiceHandler) GetFaultStateByCursor(cursor *fMgrd.StateCursor, filter *fMgrd.StateFilter) (*fMgrd.FaultStateCursorInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get call for Faults by cursor", cursor, filter))
+	var cursorObj fMgrd.FaultStateCursorInfo
+	info, err := api.GetFaultByCursor(convertToObjFmtStateCursor(cursor), convertToObjFmtStateFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+	cursorObj.Count = fMgrd.Int(info.Count)
+	cursorObj.More = info.More
+	cursorObj.NextSeqNumber = info.NextSeqNumber
+	for idx := 0; idx < info.Count; idx++ {
+		cursorObj.FaultStateList = append(cursorObj.FaultStateList, convertToRPCFmtFaultState(info.List[idx]))
+	}
+	return &cursorObj, nil
+}
+
+func (h *rpcServiceHandler) GetAlarmStateByCursor(cursor *fMgrd.StateCursor, filter *fMgrd.StateFilter) (*fMgrd.AlarmStateCursorInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get call for Alarms by cursor", cursor, filter))
+	var cursorObj fMgrd.AlarmStateCursorInfo
+	info, err := api.GetAlarmByCursor(convertToObjFmtStateCursor(cursor), convertToObjFmtStateFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+	cursorObj.Count = fMgrd.Int(info.Count)
+	cursorObj.More = info.More
+	cursorObj.NextSeqNumber = info.NextSeqNumber
+	for idx := 0; idx < info.Count; idx++ {
+		cursorObj.AlarmStateList = append(cursorObj.AlarmStateList, convertToRPCFmtAlarmState(info.List[idx]))
+	}
+	return &cursorObj, nil
+}
+
+func (h *rpcServiceHandler) GetAlarmState(ownerId int32, eventId int32, ownerName string, eventName string, srcObjName string, srcObjUUID string) (*fMgrd.AlarmState, error) {
+	h.logger.Info(fmt.Sprintln("Get call for Alarm", ownerId, eventId, ownerName, eventName, srcObjName, srcObjUUID))
+	obj, err := api.GetAlarm(int(ownerId), int(eventId), ownerName, eventName, srcObjName, srcObjUUID)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtAlarmState(*obj), nil
+}
+
+func (h *rpcServiceHandler) GetBulkActiveFault(fromIndex fMgrd.Int, count fMgrd.Int) (*fMgrd.ActiveFaultGetInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get bulk call for Active Faults"))
+	var getBulkObj fMgrd.ActiveFaultGetInfo
+	info, err := api.GetBulkActiveFault(int(fromIndex), int(count))
+	if err != nil {
+		return nil, err
+	}
+	getBulkObj.StartIdx = fMgrd.Int(fromIndex)
+	getBulkObj.EndIdx = fMgrd.Int(info.EndIdx)
+	getBulkObj.More = info.More
+	getBulkObj.Count = fMgrd.Int(info.Count)
+	for idx := 0; idx < info.Count; idx++ {
+		getBulkObj.ActiveFaultList = append(getBulkObj.ActiveFaultList, convertToRPCFmtActiveFault(info.List[idx]))
+	}
+	return &getBulkObj, err
+}
+
+func (h *rpcServiceHandler) GetActiveFault(ownerName string, eventName string, srcObjUUID string) (*fMgrd.ActiveFault, error) {
+	h.logger.Info(fmt.Sprintln("Get call for Active Fault", ownerName, eventName, srcObjUUID))
+	obj, err := api.GetActiveFault(ownerName, eventName, srcObjUUID)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtActiveFault(*obj), nil
+}
+
+func (h *rpcServiceHandler) GetBulkActiveAlarm(fromIndex fMgrd.Int, count fMgrd.Int) (*fMgrd.ActiveAlarmGetInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get bulk call for Active Alarms"))
+	var getBulkObj fMgrd.ActiveAlarmGetInfo
+	info, err := api.GetBulkActiveAlarm(int(fromIndex), int(count))
+	if err != nil {
+		return nil, err
+	}
+	getBulkObj.StartIdx = fMgrd.Int(fromIndex)
+	getBulkObj.EndIdx = fMgrd.Int(info.EndIdx)
+	getBulkObj.More = info.More
+	getBulkObj.Count = fMgrd.Int(info.Count)
+	for idx := 0; idx < info.Count; idx++ {
+		getBulkObj.ActiveAlarmList = append(getBulkObj.ActiveAlarmList, convertToRPCFmtActiveAlarm(info.List[idx]))
+	}
+	return &getBulkObj, err
+}
+
+func (h *rpcServiceHandler) GetActiveAlarm(ownerName string, eventName string, srcObjUUID string) (*fMgrd.ActiveAlarm, error) {
+	h.logger.Info(fmt.Sprintln("Get call for Active Alarm", ownerName, eventName, srcObjUUID))
+	obj, err := api.GetActiveAlarm(ownerName, eventName, srcObjUUID)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtActiveAlarm(*obj), nil
 }
 
 func (h *rpcServiceHandler) ExecuteActionFaultEnable(config *fMgrd.FaultEnable) (bool, error) {
@@ -97,3 +211,206 @@ func (h *rpcServiceHandler) ExecuteActionFaultClear(config *fMgrd.FaultClear) (b
 
 	return api.FaultClearAction(convertToObjFmtFaultClear(config))
 }
+
+func (h *rpcServiceHandler) ExecuteActionAlarmAck(config *fMgrd.AlarmAck) (bool, error) {
+	h.logger.Info(fmt.Sprintln("ExecuteActionAlarmAck ", config))
+
+	return api.AlarmAckAction(convertToObjFmtAlarmAck(config))
+}
+
+func (h *rpcServiceHandler) ExecuteActionAlarmShelve(config *fMgrd.AlarmShelve) (bool, error) {
+	h.logger.Info(fmt.Sprintln("ExecuteActionAlarmShelve ", config))
+
+	return api.AlarmShelveAction(convertToObjFmtAlarmShelve(config))
+}
+
+func (h *rpcServiceHandler) CreateEventHoldTime(conf *fMgrd.EventHoldTime) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received CreateEventHoldTime call", conf))
+	return api.CreateEventHoldTime(convertToObjFmtEventHoldTime(conf))
+}
+
+func (h *rpcServiceHandler) UpdateEventHoldTime(origConf *fMgrd.EventHoldTime, newConf *fMgrd.EventHoldTime, attrset []bool, op []*fMgrd.PatchOpInfo) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Update EventHoldTime config attrs:", origConf, newConf, attrset))
+	return api.UpdateEventHoldTime(convertToObjFmtEventHoldTime(origConf), convertToObjFmtEventHoldTime(newConf), attrset)
+}
+
+func (h *rpcServiceHandler) DeleteEventHoldTime(conf *fMgrd.EventHoldTime) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received DeleteEventHoldTime call", conf))
+	return api.DeleteEventHoldTime(convertToObjFmtEventHoldTime(conf))
+}
+
+func (h *rpcServiceHandler) CreateAlarmEscalation(conf *fMgrd.AlarmEscalation) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received CreateAlarmEscalation call", conf))
+	return api.CreateAlarmEscalation(convertToObjFmtAlarmEscalation(conf))
+}
+
+func (h *rpcServiceHandler) UpdateAlarmEscalation(origConf *fMgrd.AlarmEscalation, newConf *fMgrd.AlarmEscalation, attrset []bool, op []*fMgrd.PatchOpInfo) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Update AlarmEscalation config attrs:", origConf, newConf, attrset))
+	return api.UpdateAlarmEscalation(convertToObjFmtAlarmEscalation(origConf), convertToObjFmtAlarmEscalation(newConf), attrset)
+}
+
+func (h *rpcServiceHandler) DeleteAlarmEscalation(conf *fMgrd.AlarmEscalation) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received DeleteAlarmEscalation call", conf))
+	return api.DeleteAlarmEscalation(convertToObjFmtAlarmEscalation(conf))
+}
+
+func (h *rpcServiceHandler) CreateSnmpTrapReceiver(conf *fMgrd.SnmpTrapReceiver) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received CreateSnmpTrapReceiver call", conf))
+	return api.CreateSnmpTrapReceiver(convertToObjFmtSnmpTrapReceiver(conf))
+}
+
+func (h *rpcServiceHandler) UpdateSnmpTrapReceiver(origConf *fMgrd.SnmpTrapReceiver, newConf *fMgrd.SnmpTrapReceiver, attrset []bool, op []*fMgrd.PatchOpInfo) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Update SnmpTrapReceiver config attrs:", origConf, newConf, attrset))
+	return api.UpdateSnmpTrapReceiver(convertToObjFmtSnmpTrapReceiver(origConf), convertToObjFmtSnmpTrapReceiver(newConf), attrset)
+}
+
+func (h *rpcServiceHandler) DeleteSnmpTrapReceiver(conf *fMgrd.SnmpTrapReceiver) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received DeleteSnmpTrapReceiver call", conf))
+	return api.DeleteSnmpTrapReceiver(convertToObjFmtSnmpTrapReceiver(conf))
+}
+
+func (h *rpcServiceHandler) CreateSyslogServer(conf *fMgrd.SyslogServer) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received CreateSyslogServer call", conf))
+	return api.CreateSyslogServer(convertToObjFmtSyslogServer(conf))
+}
+
+func (h *rpcServiceHandler) UpdateSyslogServer(origConf *fMgrd.SyslogServer, newConf *fMgrd.SyslogServer, attrset []bool, op []*fMgrd.PatchOpInfo) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Update SyslogServer config attrs:", origConf, newConf, attrset))
+	return api.UpdateSyslogServer(convertToObjFmtSyslogServer(origConf), convertToObjFmtSyslogServer(newConf), attrset)
+}
+
+func (h *rpcServiceHandler) DeleteSyslogServer(conf *fMgrd.SyslogServer) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received DeleteSyslogServer call", conf))
+	return api.DeleteSyslogServer(convertToObjFmtSyslogServer(conf))
+}
+
+func (h *rpcServiceHandler) ExecuteActionEventsReload(config *fMgrd.EventsReload) (bool, error) {
+	h.logger.Info(fmt.Sprintln("ExecuteActionEventsReload ", config))
+
+	return api.EventsReloadAction(convertToObjFmtEventsReload(config))
+}
+
+func (h *rpcServiceHandler) GetEventsReloadState(vrf string) (*fMgrd.EventsReloadState, error) {
+	h.logger.Info(fmt.Sprintln("Get call for EventsReloadState", vrf))
+	obj, err := api.GetEventsReloadState(vrf)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtEventsReloadState(*obj), nil
+}
+
+func (h *rpcServiceHandler) GetBulkEventsReloadState(fromIdx fMgrd.Int, count fMgrd.Int) (*fMgrd.EventsReloadStateGetInfo, error) {
+	var getBulkObj fMgrd.EventsReloadStateGetInfo
+	obj, err := api.GetEventsReloadState("default")
+	if err != nil {
+		return nil, err
+	}
+	getBulkObj.StartIdx = fromIdx
+	getBulkObj.EndIdx = fMgrd.Int(1)
+	getBulkObj.Count = fMgrd.Int(1)
+	getBulkObj.More = false
+	getBulkObj.EventsReloadStateList = append(getBulkObj.EventsReloadStateList, convertToRPCFmtEventsReloadState(*obj))
+	return &getBulkObj, nil
+}
+
+func (h *rpcServiceHandler) GetAlarmSummary(vrf string) (*fMgrd.AlarmSummary, error) {
+	h.logger.Info(fmt.Sprintln("Get call for AlarmSummary", vrf))
+	obj, err := api.GetAlarmSummary(vrf)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtAlarmSummary(*obj), nil
+}
+
+func (h *rpcServiceHandler) GetBulkAlarmSummary(fromIdx fMgrd.Int, count fMgrd.Int) (*fMgrd.AlarmSummaryGetInfo, error) {
+	var getBulkObj fMgrd.AlarmSummaryGetInfo
+	obj, err := api.GetAlarmSummary("default")
+	if err != nil {
+		return nil, err
+	}
+	getBulkObj.StartIdx = fromIdx
+	getBulkObj.EndIdx = fMgrd.Int(1)
+	getBulkObj.Count = fMgrd.Int(1)
+	getBulkObj.More = false
+	getBulkObj.AlarmSummaryList = append(getBulkObj.AlarmSummaryList, convertToRPCFmtAlarmSummary(*obj))
+	return &getBulkObj, nil
+}
+
+func (h *rpcServiceHandler) ExecuteActionFaultInject(config *fMgrd.FaultInject) (bool, error) {
+	h.logger.Info(fmt.Sprintln("ExecuteActionFaultInject ", config))
+
+	return api.FaultInjectAction(convertToObjFmtFaultInject(config))
+}
+
+func (h *rpcServiceHandler) ExecuteActionHistoryExport(config *fMgrd.HistoryExport) (bool, error) {
+	h.logger.Info(fmt.Sprintln("ExecuteActionHistoryExport ", config))
+
+	return api.HistoryExportAction(convertToObjFmtHistoryExport(config))
+}
+
+func (h *rpcServiceHandler) GetHistoryExportState(fileName string) (*fMgrd.HistoryExportState, error) {
+	h.logger.Info(fmt.Sprintln("Get call for HistoryExportState", fileName))
+	obj, err := api.GetHistoryExportState(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtHistoryExportState(*obj), nil
+}
+
+func (h *rpcServiceHandler) GetBulkHistoryExportState(fromIdx fMgrd.Int, count fMgrd.Int) (*fMgrd.HistoryExportStateGetInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get bulk call for HistoryExportState"))
+	var getBulkObj fMgrd.HistoryExportStateGetInfo
+	info, err := api.GetBulkHistoryExportState(int(fromIdx), int(count))
+	if err != nil {
+		return nil, err
+	}
+	getBulkObj.StartIdx = fromIdx
+	getBulkObj.EndIdx = fMgrd.Int(info.EndIdx)
+	getBulkObj.More = info.More
+	getBulkObj.Count = fMgrd.Int(info.Count)
+	for idx := 0; idx < info.Count; idx++ {
+		getBulkObj.HistoryExportStateList = append(getBulkObj.HistoryExportStateList, convertToRPCFmtHistoryExportState(info.List[idx]))
+	}
+	return &getBulkObj, nil
+}
+
+func (h *rpcServiceHandler) CreateMaintenanceWindow(conf *fMgrd.MaintenanceWindow) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received CreateMaintenanceWindow call", conf))
+	return api.CreateMaintenanceWindow(convertToObjFmtMaintenanceWindow(conf))
+}
+
+func (h *rpcServiceHandler) UpdateMaintenanceWindow(origConf *fMgrd.MaintenanceWindow, newConf *fMgrd.MaintenanceWindow, attrset []bool, op []*fMgrd.PatchOpInfo) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Update Maintenance Window config attrs:", origConf, newConf, attrset))
+	return api.UpdateMaintenanceWindow(convertToObjFmtMaintenanceWindow(origConf), convertToObjFmtMaintenanceWindow(newConf), attrset)
+}
+
+func (h *rpcServiceHandler) DeleteMaintenanceWindow(conf *fMgrd.MaintenanceWindow) (bool, error) {
+	h.logger.Info(fmt.Sprintln("Received DeleteMaintenanceWindow call", conf))
+	return api.DeleteMaintenanceWindow(convertToObjFmtMaintenanceWindow(conf))
+}
+
+func (h *rpcServiceHandler) GetEventIngestState(ownerName string) (*fMgrd.EventIngestState, error) {
+	h.logger.Info(fmt.Sprintln("Get call for EventIngestState", ownerName))
+	obj, err := api.GetEventIngestState(ownerName)
+	if err != nil {
+		return nil, err
+	}
+	return convertToRPCFmtEventIngestState(*obj), nil
+}
+
+func (h *rpcServiceHandler) GetBulkEventIngestState(fromIdx fMgrd.Int, count fMgrd.Int) (*fMgrd.EventIngestStateGetInfo, error) {
+	h.logger.Info(fmt.Sprintln("Get bulk call for EventIngestState"))
+	var getBulkObj fMgrd.EventIngestStateGetInfo
+	info, err := api.GetBulkEventIngestState(int(fromIdx), int(count))
+	if err != nil {
+		return nil, err
+	}
+	getBulkObj.StartIdx = fromIdx
+	getBulkObj.EndIdx = fMgrd.Int(info.EndIdx)
+	getBulkObj.More = info.More
+	getBulkObj.Count = fMgrd.Int(info.Count)
+	for idx := 0; idx < info.Count; idx++ {
+		getBulkObj.EventIngestStateList = append(getBulkObj.EventIngestStateList, convertToRPCFmtEventIngestState(info.List[idx]))
+	}
+	return &getBulkObj, nil
+}
@@ -41,6 +41,12 @@ func convertToRPCFmtFaultState(obj objects.FaultState) *fMgrd.FaultState {
 		SrcObjUUID:       obj.SrcObjUUID,
 		ResolutionTime:   obj.ResolutionTime,
 		ResolutionReason: obj.ResolutionReason,
+		FlapCount:        obj.FlapCount,
+		OccurrenceCount:  obj.OccurrenceCount,
+		LastSeenTime:     obj.LastSeenTime,
+		SeqNumber:        obj.SeqNumber,
+		Injected:         obj.Injected,
+		InMaintenance:    obj.InMaintenance,
 	}
 }
 
@@ -58,6 +64,73 @@ func convertToRPCFmtAlarmState(obj objects.AlarmState) *fMgrd.AlarmState {
 		ResolutionTime:   obj.ResolutionTime,
 		Severity:         obj.Severity,
 		ResolutionReason: obj.ResolutionReason,
+		Acknowledged:     obj.Acknowledged,
+		AckedBy:          obj.AckedBy,
+		AckTime:          obj.AckTime,
+		AckNote:          obj.AckNote,
+		Flapping:         obj.Flapping,
+		FlapCount:        obj.FlapCount,
+		EscalationLevel:  obj.EscalationLevel,
+		Suppressed:       obj.Suppressed,
+		SuppressedBy:     obj.SuppressedBy,
+		OccurrenceCount:  obj.OccurrenceCount,
+		LastSeenTime:     obj.LastSeenTime,
+		SeqNumber:        obj.SeqNumber,
+		Injected:         obj.Injected,
+	}
+}
+
+func convertToRPCFmtActiveFault(obj objects.ActiveFault) *fMgrd.ActiveFault {
+	return &fMgrd.ActiveFault{
+		OwnerId:       obj.OwnerId,
+		EventId:       obj.EventId,
+		OwnerName:     obj.OwnerName,
+		EventName:     obj.EventName,
+		SrcObjName:    obj.SrcObjName,
+		Description:   obj.Description,
+		OccuranceTime: obj.OccuranceTime,
+		SrcObjKey:     obj.SrcObjKey,
+		SrcObjUUID:    obj.SrcObjUUID,
+	}
+}
+
+func convertToRPCFmtActiveAlarm(obj objects.ActiveAlarm) *fMgrd.ActiveAlarm {
+	return &fMgrd.ActiveAlarm{
+		OwnerId:         obj.OwnerId,
+		EventId:         obj.EventId,
+		OwnerName:       obj.OwnerName,
+		EventName:       obj.EventName,
+		SrcObjName:      obj.SrcObjName,
+		Severity:        obj.Severity,
+		Description:     obj.Description,
+		OccuranceTime:   obj.OccuranceTime,
+		SrcObjKey:       obj.SrcObjKey,
+		SrcObjUUID:      obj.SrcObjUUID,
+		Acknowledged:    obj.Acknowledged,
+		AckedBy:         obj.AckedBy,
+		AckTime:         obj.AckTime,
+		AckNote:         obj.AckNote,
+		Flapping:        obj.Flapping,
+		FlapCount:       obj.FlapCount,
+		EscalationLevel: obj.EscalationLevel,
+		Suppressed:      obj.Suppressed,
+		SuppressedBy:    obj.SuppressedBy,
+	}
+}
+
+func convertToObjFmtFMgrGlobal(config *fMgrd.FMgrGlobal) *objects.FMgrGlobal {
+	return &objects.FMgrGlobal{
+		Vrf:                        config.Vrf,
+		FaultToAlarmTransitionTime: config.FaultToAlarmTransitionTime,
+		AlarmTransitionTime:        config.AlarmTransitionTime,
+		FlapWindow:                 config.FlapWindow,
+		FlapThreshold:              config.FlapThreshold,
+		FlapQuietPeriod:            config.FlapQuietPeriod,
+		FaultHistorySize:           config.FaultHistorySize,
+		AlarmHistorySize:           config.AlarmHistorySize,
+		HistoryRetention:           config.HistoryRetention,
+		HistoryExportDir:           config.HistoryExportDir,
+		EventOverflowPolicy:        config.EventOverflowPolicy,
 	}
 }
 
@@ -76,3 +149,187 @@ func convertToObjFmtFaultClear(config *fMgrd.FaultClear) *objects.FaultClear {
 		SrcObjUUID: config.SrcObjUUID,
 	}
 }
+
+func convertToObjFmtAlarmAck(config *fMgrd.AlarmAck) *objects.AlarmAck {
+	return &objects.AlarmAck{
+		OwnerName:  config.OwnerName,
+		EventName:  config.EventName,
+		SrcObjUUID: config.SrcObjUUID,
+		Ack:        config.Ack,
+		AckedBy:    config.AckedBy,
+		Note:       config.Note,
+	}
+}
+
+func convertToObjFmtAlarmShelve(config *fMgrd.AlarmShelve) *objects.AlarmShelve {
+	return &objects.AlarmShelve{
+		OwnerName:  config.OwnerName,
+		EventName:  config.EventName,
+		SrcObjUUID: config.SrcObjUUID,
+		Duration:   config.Duration,
+	}
+}
+
+func convertToObjFmtEventHoldTime(config *fMgrd.EventHoldTime) *objects.EventHoldTime {
+	return &objects.EventHoldTime{
+		OwnerName:                  config.OwnerName,
+		EventName:                  config.EventName,
+		FaultToAlarmTransitionTime: config.FaultToAlarmTransitionTime,
+		AlarmTransitionTime:        config.AlarmTransitionTime,
+	}
+}
+
+func convertToObjFmtStateCursor(cursor *fMgrd.StateCursor) *objects.StateCursor {
+	if cursor == nil {
+		return nil
+	}
+	return &objects.StateCursor{
+		SeqNumber: cursor.SeqNumber,
+		Direction: cursor.Direction,
+		Count:     int(cursor.Count),
+	}
+}
+
+func convertToObjFmtStateFilter(filter *fMgrd.StateFilter) *objects.StateFilter {
+	if filter == nil {
+		return nil
+	}
+	return &objects.StateFilter{
+		OwnerName:  filter.OwnerName,
+		EventName:  filter.EventName,
+		Severity:   filter.Severity,
+		SrcObjKey:  filter.SrcObjKey,
+		SrcObjUUID: filter.SrcObjUUID,
+		Resolved:   filter.Resolved,
+		FromTime:   filter.FromTime,
+		ToTime:     filter.ToTime,
+	}
+}
+
+func convertToObjFmtAlarmEscalation(config *fMgrd.AlarmEscalation) *objects.AlarmEscalation {
+	return &objects.AlarmEscalation{
+		OwnerName:          config.OwnerName,
+		EventName:          config.EventName,
+		EscalationInterval: config.EscalationInterval,
+		MaxSeverity:        config.MaxSeverity,
+	}
+}
+
+func convertToObjFmtSnmpTrapReceiver(config *fMgrd.SnmpTrapReceiver) *objects.SnmpTrapReceiver {
+	return &objects.SnmpTrapReceiver{
+		IpAddr:       config.IpAddr,
+		Port:         config.Port,
+		Version:      config.Version,
+		Community:    config.Community,
+		Inform:       config.Inform,
+		SecurityName: config.SecurityName,
+		AuthProtocol: config.AuthProtocol,
+		AuthPassword: config.AuthPassword,
+		PrivProtocol: config.PrivProtocol,
+		PrivPassword: config.PrivPassword,
+	}
+}
+
+func convertToObjFmtSyslogServer(config *fMgrd.SyslogServer) *objects.SyslogServer {
+	return &objects.SyslogServer{
+		IpAddr:        config.IpAddr,
+		Port:          config.Port,
+		Transport:     config.Transport,
+		Facility:      config.Facility,
+		CACertFile:    config.CACertFile,
+		TLSSkipVerify: config.TLSSkipVerify,
+	}
+}
+
+func convertToObjFmtEventsReload(config *fMgrd.EventsReload) *objects.EventsReload {
+	return &objects.EventsReload{
+		Vrf: config.Vrf,
+	}
+}
+
+func convertToRPCFmtEventsReloadState(obj objects.EventsReloadState) *fMgrd.EventsReloadState {
+	return &fMgrd.EventsReloadState{
+		Vrf:            obj.Vrf,
+		LastReloadTime: obj.LastReloadTime,
+		Status:         obj.Status,
+		AddedEvents:    obj.AddedEvents,
+		RemovedEvents:  obj.RemovedEvents,
+		ModifiedEvents: obj.ModifiedEvents,
+	}
+}
+
+func convertToRPCFmtAlarmSummary(obj objects.AlarmSummary) *fMgrd.AlarmSummary {
+	var ownerSummary []*fMgrd.AlarmOwnerCount
+	for _, ownerCount := range obj.OwnerSummary {
+		ownerSummary = append(ownerSummary, &fMgrd.AlarmOwnerCount{
+			OwnerName: ownerCount.OwnerName,
+			Count:     fMgrd.Int(ownerCount.Count),
+		})
+	}
+	return &fMgrd.AlarmSummary{
+		Vrf:             obj.Vrf,
+		TotalCount:      fMgrd.Int(obj.TotalCount),
+		CriticalCount:   fMgrd.Int(obj.CriticalCount),
+		MajorCount:      fMgrd.Int(obj.MajorCount),
+		MinorCount:      fMgrd.Int(obj.MinorCount),
+		WarningCount:    fMgrd.Int(obj.WarningCount),
+		OtherCount:      fMgrd.Int(obj.OtherCount),
+		HighestSeverity: obj.HighestSeverity,
+		OwnerSummary:    ownerSummary,
+	}
+}
+
+func convertToObjFmtFaultInject(config *fMgrd.FaultInject) *objects.FaultInject {
+	return &objects.FaultInject{
+		OwnerName:   config.OwnerName,
+		EventName:   config.EventName,
+		SrcObjKey:   config.SrcObjKey,
+		Description: config.Description,
+	}
+}
+
+func convertToObjFmtHistoryExport(config *fMgrd.HistoryExport) *objects.HistoryExport {
+	return &objects.HistoryExport{
+		FileName:  config.FileName,
+		Format:    config.Format,
+		OwnerName: config.OwnerName,
+		FromTime:  config.FromTime,
+		ToTime:    config.ToTime,
+	}
+}
+
+func convertToRPCFmtHistoryExportState(obj objects.HistoryExportState) *fMgrd.HistoryExportState {
+	return &fMgrd.HistoryExportState{
+		FileName:   obj.FileName,
+		Path:       obj.Path,
+		Format:     obj.Format,
+		ExportTime: obj.ExportTime,
+		Status:     obj.Status,
+		FaultCount: obj.FaultCount,
+		AlarmCount: obj.AlarmCount,
+	}
+}
+
+func convertToObjFmtMaintenanceWindow(config *fMgrd.MaintenanceWindow) *objects.MaintenanceWindow {
+	return &objects.MaintenanceWindow{
+		Name:               config.Name,
+		OwnerName:          config.OwnerName,
+		SrcObjKey:          config.SrcObjKey,
+		StartTime:          config.StartTime,
+		EndTime:            config.EndTime,
+		RecurrenceInterval: config.RecurrenceInterval,
+	}
+}
+
+func convertToRPCFmtEventIngestState(obj objects.EventIngestState) *fMgrd.EventIngestState {
+	return &fMgrd.EventIngestState{
+		OwnerName:        obj.OwnerName,
+		Received:         obj.Received,
+		Processed:        obj.Processed,
+		UnmarshalErrors:  obj.UnmarshalErrors,
+		UnknownEvents:    obj.UnknownEvents,
+		EnableSuppressed: obj.EnableSuppressed,
+		ChannelFull:      obj.ChannelFull,
+		Dropped:          obj.Dropped,
+	}
+}
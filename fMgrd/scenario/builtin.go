@@ -0,0 +1,340 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package scenario
+
+import (
+	"infra/fMgrd/faultMgr"
+	"utils/eventUtils"
+)
+
+const (
+	SCENARIO_OWNER      = "asicd"
+	SCENARIO_FAULT      = "PortOperStateDown"
+	SCENARIO_CLEAR      = "PortOperStateUp"
+	SCENARIO_SRC_OBJ    = "Port"
+	SCENARIO_FAULT_CHAN = SCENARIO_OWNER + "Faults"
+	SCENARIO_ALARM_CHAN = SCENARIO_OWNER + "Alarms"
+)
+
+// BuiltinEvents defines a port down fault cleared by port up, it is used by
+// the builtin scenarios
+var BuiltinEvents = &eventUtils.EventJson{
+	DaemonEvents: []eventUtils.DaemonEvent{
+		eventUtils.DaemonEvent{
+			DaemonId:   1,
+			DaemonName: SCENARIO_OWNER,
+			EventList: []eventUtils.EventStruct{
+				eventUtils.EventStruct{
+					EventId:     1,
+					EventName:   SCENARIO_FAULT,
+					Description: "Port Operational State Down",
+					SrcObjName:  SCENARIO_SRC_OBJ,
+					IsFault:     true,
+					Fault: eventUtils.FaultDetail{
+						RaiseFault:       true,
+						ClearingEventId:  2,
+						ClearingDaemonId: 1,
+						AlarmSeverity:    "Major",
+					},
+				},
+				eventUtils.EventStruct{
+					EventId:     2,
+					EventName:   SCENARIO_CLEAR,
+					Description: "Port Operational State Up",
+					SrcObjName:  SCENARIO_SRC_OBJ,
+				},
+			},
+		},
+	},
+}
+
+func count(val int) *int {
+	return &val
+}
+
+func portEvent(eventName, intfRef string) Step {
+	return Step{
+		Event: &EventStep{
+			OwnerName: SCENARIO_OWNER,
+			EventName: eventName,
+			SrcObjKey: map[string]interface{}{"IntfRef": intfRef},
+		},
+	}
+}
+
+func advance(duration string) Step {
+	return Step{Advance: duration}
+}
+
+func action(name string, enable bool) Step {
+	return Step{
+		Action: &ActionStep{
+			Name:      name,
+			OwnerName: SCENARIO_OWNER,
+			EventName: SCENARIO_FAULT,
+			Enable:    enable,
+		},
+	}
+}
+
+func inject(eventName, intfRef string) Step {
+	return Step{
+		Action: &ActionStep{
+			Name:      FAULT_INJECT_ACTION,
+			OwnerName: SCENARIO_OWNER,
+			EventName: eventName,
+			SrcObjKey: map[string]interface{}{"IntfRef": intfRef},
+		},
+	}
+}
+
+func shelve(duration int32) Step {
+	return Step{
+		Action: &ActionStep{
+			Name:      ALARM_SHELVE_ACTION,
+			OwnerName: SCENARIO_OWNER,
+			EventName: SCENARIO_FAULT,
+			Duration:  duration,
+		},
+	}
+}
+
+func queuePortEvent(eventName, intfRef string, count int) Step {
+	step := portEvent(eventName, intfRef)
+	step.Event.Queued = true
+	step.Event.Count = count
+	return step
+}
+
+func overflowPolicy(policy string) Step {
+	return Step{
+		Action: &ActionStep{
+			Name:   FMGR_GLOBAL_ACTION,
+			Policy: policy,
+		},
+	}
+}
+
+func maintenance(intfRef string, duration int32) Step {
+	return Step{
+		Action: &ActionStep{
+			Name:      MAINTENANCE_ACTION,
+			OwnerName: SCENARIO_OWNER,
+			SrcObjKey: map[string]interface{}{"IntfRef": intfRef},
+			Duration:  duration,
+		},
+	}
+}
+
+func daemonStatus(daemon, status string) Step {
+	return Step{
+		Action: &ActionStep{
+			Name:   DAEMON_STATUS_ACTION,
+			Daemon: daemon,
+			Status: status,
+		},
+	}
+}
+
+// BuiltinScenarios cover the fault/alarm lifecycle with the default 3s
+// fault to alarm and alarm clear hold times
+var BuiltinScenarios = []Scenario{
+	Scenario{
+		Name: "RaiseAndClear",
+		Steps: []Step{
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			Step{Expect: &Expectation{ActiveFaults: count(1), ActiveAlarms: count(0), Faults: count(1)}},
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveAlarms: count(1), Alarms: count(1)}},
+			portEvent(SCENARIO_CLEAR, "fpPort1"),
+			Step{Expect: &Expectation{ActiveFaults: count(0), ActiveAlarms: count(1), ResolvedFaults: count(1)}},
+			advance("3s"),
+			Step{Expect: &Expectation{
+				ActiveAlarms:   count(0),
+				Faults:         count(1),
+				Alarms:         count(1),
+				ResolvedAlarms: count(1),
+				Published:      map[string]int{SCENARIO_FAULT_CHAN: 2, SCENARIO_ALARM_CHAN: 2},
+			}},
+		},
+	},
+	Scenario{
+		Name: "ReraiseBeforeAlarm",
+		Steps: []Step{
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			advance("1s"),
+			portEvent(SCENARIO_CLEAR, "fpPort1"),
+			advance("1s"),
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			Step{Expect: &Expectation{ActiveFaults: count(1), Faults: count(2), ResolvedFaults: count(1)}},
+			// The alarm timer of the first fault must not fire
+			advance("2s"),
+			Step{Expect: &Expectation{Alarms: count(0)}},
+			advance("1s"),
+			Step{Expect: &Expectation{ActiveAlarms: count(1), Alarms: count(1)}},
+		},
+	},
+	Scenario{
+		Name: "Disable",
+		Steps: []Step{
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			advance("3s"),
+			action(FAULT_ENABLE_ACTION, false),
+			Step{Expect: &Expectation{
+				ActiveFaults:   count(0),
+				ActiveAlarms:   count(0),
+				ResolvedFaults: count(1),
+				ResolvedAlarms: count(1),
+			}},
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			advance("3s"),
+			Step{Expect: &Expectation{Faults: count(1), Alarms: count(1)}},
+			action(FAULT_ENABLE_ACTION, true),
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			Step{Expect: &Expectation{ActiveFaults: count(1), Faults: count(2)}},
+		},
+	},
+	Scenario{
+		Name: "ManualClear",
+		Steps: []Step{
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			portEvent(SCENARIO_FAULT, "fpPort2"),
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveFaults: count(2), ActiveAlarms: count(2)}},
+			action(FAULT_CLEAR_ACTION, false),
+			Step{Expect: &Expectation{
+				ActiveFaults:   count(0),
+				ActiveAlarms:   count(0),
+				ResolvedFaults: count(2),
+				ResolvedAlarms: count(2),
+			}},
+			// The port coming up afterwards has nothing left to clear
+			portEvent(SCENARIO_CLEAR, "fpPort1"),
+			advance("3s"),
+			Step{Expect: &Expectation{Faults: count(2), Alarms: count(2)}},
+		},
+	},
+	Scenario{
+		Name: "Inject",
+		Steps: []Step{
+			inject(SCENARIO_FAULT, "fpPort1"),
+			portEvent(SCENARIO_FAULT, "fpPort2"),
+			advance("3s"),
+			Step{Expect: &Expectation{
+				ActiveAlarms:   count(2),
+				InjectedFaults: count(1),
+				InjectedAlarms: count(1),
+			}},
+			inject(SCENARIO_CLEAR, "fpPort1"),
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveFaults: count(1), ActiveAlarms: count(1), ResolvedAlarms: count(1)}},
+		},
+	},
+	Scenario{
+		Name: "Shelve",
+		Steps: []Step{
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			advance("3s"),
+			shelve(60),
+			portEvent(SCENARIO_FAULT, "fpPort2"),
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveFaults: count(2), ActiveAlarms: count(1), Alarms: count(1)}},
+			// The republish of the re-raised fault does not publish the shelved alarm
+			advance("30s"),
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			Step{Expect: &Expectation{Published: map[string]int{SCENARIO_FAULT_CHAN: 3, SCENARIO_ALARM_CHAN: 1}}},
+			// On expiry the withheld alarm is raised and the active one published again
+			advance("27s"),
+			Step{Expect: &Expectation{
+				ActiveAlarms: count(2),
+				Alarms:       count(2),
+				Published:    map[string]int{SCENARIO_FAULT_CHAN: 3, SCENARIO_ALARM_CHAN: 3},
+			}},
+		},
+	},
+	Scenario{
+		Name: "Maintenance",
+		Steps: []Step{
+			maintenance("fpPort1", 60),
+			portEvent(SCENARIO_FAULT, "fpPort1"),
+			portEvent(SCENARIO_FAULT, "fpPort2"),
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveFaults: count(2), ActiveAlarms: count(1)}},
+			// The fault left once the window ends turns into an alarm
+			advance("57s"),
+			Step{Expect: &Expectation{ActiveAlarms: count(1)}},
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveAlarms: count(2), Alarms: count(2)}},
+		},
+	},
+	Scenario{
+		Name: "DaemonDown",
+		Steps: []Step{
+			daemonStatus("bgpd", "stopped"),
+			Step{Expect: &Expectation{ActiveFaults: count(1), Faults: count(1)}},
+			// Restarting does not raise the fault again
+			daemonStatus("bgpd", "restarting"),
+			advance("3s"),
+			Step{Expect: &Expectation{ActiveFaults: count(1), ActiveAlarms: count(1), Faults: count(1)}},
+			daemonStatus("bgpd", "up"),
+			advance("3s"),
+			Step{Expect: &Expectation{
+				ActiveFaults:   count(0),
+				ActiveAlarms:   count(0),
+				ResolvedFaults: count(1),
+				ResolvedAlarms: count(1),
+			}},
+		},
+	},
+	Scenario{
+		Name: "OverflowDropNewest",
+		Steps: []Step{
+			overflowPolicy(faultMgr.OVERFLOW_DROP_NEWEST),
+			queuePortEvent(SCENARIO_FAULT, "fpPort1", faultMgr.EVENT_CH_SIZE),
+			queuePortEvent(SCENARIO_FAULT, "fpPort2", 1),
+			Step{ProcessQueued: true},
+			Step{Expect: &Expectation{
+				ActiveFaults:    count(1),
+				EventsProcessed: map[string]int{SCENARIO_OWNER: faultMgr.EVENT_CH_SIZE},
+				EventsDropped:   map[string]int{SCENARIO_OWNER: 1},
+			}},
+		},
+	},
+	Scenario{
+		Name: "OverflowDropOldest",
+		Steps: []Step{
+			overflowPolicy(faultMgr.OVERFLOW_DROP_OLDEST),
+			queuePortEvent(SCENARIO_FAULT, "fpPort1", 1),
+			queuePortEvent(SCENARIO_FAULT, "fpPort2", faultMgr.EVENT_CH_SIZE-1),
+			// Drops the fault of fpPort1
+			queuePortEvent(SCENARIO_FAULT, "fpPort3", 1),
+			Step{ProcessQueued: true},
+			Step{Expect: &Expectation{
+				ActiveFaults:    count(2),
+				EventsProcessed: map[string]int{SCENARIO_OWNER: faultMgr.EVENT_CH_SIZE},
+				EventsDropped:   map[string]int{SCENARIO_OWNER: 1},
+			}},
+		},
+	},
+}
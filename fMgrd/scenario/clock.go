@@ -0,0 +1,135 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package scenario
+
+import (
+	"infra/fMgrd/faultMgr"
+	"sort"
+	"sync"
+	"time"
+)
+
+type fakeTimer struct {
+	clock   *FakeClock
+	id      uint64
+	expiry  time.Time
+	f       func()
+	stopped bool
+}
+
+// Stop follows time.Timer, false is returned if the timer already fired or
+// was stopped
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mutex.Lock()
+	defer timer.clock.mutex.Unlock()
+	if timer.stopped {
+		return false
+	}
+	timer.stopped = true
+	delete(timer.clock.timers, timer.id)
+	return true
+}
+
+// FakeClock is a manually advanced clock, timers fire synchronously from
+// Advance in the order of their expiry
+type FakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	nextId uint64
+	timers map[uint64]*fakeTimer
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{
+		now:    start,
+		timers: make(map[uint64]*fakeTimer),
+	}
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *FakeClock) AfterFunc(d time.Duration, f func()) faultMgr.Timer {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	timer := &fakeTimer{
+		clock:  clock,
+		id:     clock.nextId,
+		expiry: clock.now.Add(d),
+		f:      f,
+	}
+	clock.nextId++
+	clock.timers[timer.id] = timer
+	return timer
+}
+
+// nextTimer removes and returns the earliest timer expiring by the given
+// time, timers expiring at the same time fire in the order they were armed
+func (clock *FakeClock) nextTimer(until time.Time) *fakeTimer {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	var due []*fakeTimer
+	for _, timer := range clock.timers {
+		if !timer.expiry.After(until) {
+			due = append(due, timer)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].expiry.Equal(due[j].expiry) {
+			return due[i].id < due[j].id
+		}
+		return due[i].expiry.Before(due[j].expiry)
+	})
+	timer := due[0]
+	timer.stopped = true
+	delete(clock.timers, timer.id)
+	if timer.expiry.After(clock.now) {
+		clock.now = timer.expiry
+	}
+	return timer
+}
+
+// Advance moves the clock forward firing the timers which expire on the way,
+// including the ones armed by the fired timers
+func (clock *FakeClock) Advance(d time.Duration) {
+	until := clock.Now().Add(d)
+	for timer := clock.nextTimer(until); timer != nil; timer = clock.nextTimer(until) {
+		timer.f()
+	}
+	clock.mutex.Lock()
+	clock.now = until
+	clock.mutex.Unlock()
+}
+
+func (clock *FakeClock) PendingTimers() int {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return len(clock.timers)
+}
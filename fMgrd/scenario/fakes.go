@@ -0,0 +1,102 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package scenario
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FakeDB hands out a stable UUID per object key instead of looking it up in
+// the config DB
+type FakeDB struct {
+	mutex   sync.Mutex
+	uuidMap map[string]string
+}
+
+func NewFakeDB() *FakeDB {
+	return &FakeDB{
+		uuidMap: make(map[string]string),
+	}
+}
+
+func (db *FakeDB) Connect() error {
+	return nil
+}
+
+func (db *FakeDB) GetUUIDFromObjKey(objKey string) (string, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	uuid, exist := db.uuidMap[objKey]
+	if !exist {
+		uuid = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(db.uuidMap)+1)
+		db.uuidMap[objKey] = uuid
+	}
+	return uuid, nil
+}
+
+type PublishedMsg struct {
+	Channel string
+	Msg     []byte
+}
+
+// FakePublisher records the published messages
+type FakePublisher struct {
+	mutex sync.Mutex
+	msgs  []PublishedMsg
+}
+
+func NewFakePublisher() *FakePublisher {
+	return &FakePublisher{}
+}
+
+func (pub *FakePublisher) Connect() error {
+	return nil
+}
+
+func (pub *FakePublisher) Publish(channel string, msg []byte) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+	pub.msgs = append(pub.msgs, PublishedMsg{
+		Channel: channel,
+		Msg:     msg,
+	})
+	return nil
+}
+
+func (pub *FakePublisher) Messages() []PublishedMsg {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+	return append([]PublishedMsg(nil), pub.msgs...)
+}
+
+func (pub *FakePublisher) ChannelCount() map[string]int {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+	count := make(map[string]int)
+	for _, msg := range pub.msgs {
+		count[msg.Channel]++
+	}
+	return count
+}
@@ -0,0 +1,461 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+// Package scenario drives the fault manager through a scripted sequence of
+// events, actions and clock advances on a fake clock, DB and publisher, and
+// checks the resulting fault/alarm history and published messages.
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"infra/fMgrd/faultMgr"
+	"infra/fMgrd/objects"
+	"infra/sysd/sysdCommonDefs"
+	"math"
+	"time"
+	"utils/eventUtils"
+	"utils/logging"
+)
+
+const (
+	FAULT_ENABLE_ACTION  = "FaultEnable"
+	FAULT_CLEAR_ACTION   = "FaultClear"
+	FAULT_INJECT_ACTION  = "FaultInject"
+	ALARM_SHELVE_ACTION  = "AlarmShelve"
+	FMGR_GLOBAL_ACTION   = "FMgrGlobal"
+	DAEMON_STATUS_ACTION = "DaemonStatus"
+	MAINTENANCE_ACTION   = "MaintenanceWindow"
+)
+
+const (
+	SCENARIO_MAINTENANCE_WINDOW = "Scenario"
+)
+
+var scenarioStartTime = time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type Scenario struct {
+	Name   string
+	Events *eventUtils.EventJson // Event definitions, the runner's ones are used when not set
+	Steps  []Step
+}
+
+// Step does exactly one of raising an event, advancing the clock, executing
+// an action, processing the queued events or checking the expectations
+type Step struct {
+	Event         *EventStep
+	Advance       string // Duration like "3s"
+	Action        *ActionStep
+	ProcessQueued bool
+	Expect        *Expectation
+}
+
+// EventStep processes the event right away, unless Queued is set in which
+// case the event is queued Count times on EventCh as done by the subscriber
+type EventStep struct {
+	OwnerName   string
+	EventName   string
+	SrcObjKey   map[string]interface{}
+	Description string
+	Queued      bool
+	Count       int
+}
+
+type ActionStep struct {
+	Name        string // FaultEnable, FaultClear, FaultInject, AlarmShelve, FMgrGlobal, DaemonStatus or MaintenanceWindow
+	OwnerName   string
+	EventName   string
+	Enable      bool
+	SrcObjUUID  string
+	SrcObjKey   map[string]interface{}
+	Description string
+	Duration    int32  // Shelve or maintenance window duration in seconds, the window starts right away
+	Policy      string // EventCh overflow policy
+	Daemon      string
+	Status      string // Daemon status as published by sysd like "up" or "stopped"
+}
+
+// Expectation fields left unset are not checked, Published counts the
+// messages published per channel since the start of the scenario and
+// EventsProcessed/EventsDropped the events per owner
+type Expectation struct {
+	ActiveFaults    *int
+	ActiveAlarms    *int
+	Faults          *int
+	Alarms          *int
+	ResolvedFaults  *int
+	ResolvedAlarms  *int
+	InjectedFaults  *int
+	InjectedAlarms  *int
+	Published       map[string]int
+	EventsProcessed map[string]int
+	EventsDropped   map[string]int
+}
+
+type Result struct {
+	Name     string
+	Steps    int
+	Failures []string
+}
+
+type Runner struct {
+	logger  logging.LoggerIntf
+	evtJson *eventUtils.EventJson
+}
+
+func NewRunner(logger logging.LoggerIntf, evtJson *eventUtils.EventJson) *Runner {
+	return &Runner{
+		logger:  logger,
+		evtJson: evtJson,
+	}
+}
+
+type scenarioRun struct {
+	evtJson *eventUtils.EventJson
+	clock   *FakeClock
+	db      *FakeDB
+	pub     *FakePublisher
+	fMgr    *faultMgr.FaultManager
+	policy  string
+}
+
+// servePauseRequests stands in for EventProcessor so that the actions can
+// pause event processing, events are processed synchronously by the runner
+func servePauseRequests(fMgr *faultMgr.FaultManager, doneCh chan bool) {
+	for {
+		select {
+		case _ = <-fMgr.PauseEventProcessCh:
+			fMgr.PauseEventProcessAckCh <- true
+			<-fMgr.PauseEventProcessCh
+		case _ = <-doneCh:
+			return
+		}
+	}
+}
+
+func (runner *Runner) Run(sc *Scenario) Result {
+	result := Result{
+		Name:  sc.Name,
+		Steps: len(sc.Steps),
+	}
+	run := &scenarioRun{
+		evtJson: sc.Events,
+		clock:   NewFakeClock(scenarioStartTime),
+		db:      NewFakeDB(),
+		pub:     NewFakePublisher(),
+	}
+	if run.evtJson == nil {
+		run.evtJson = runner.evtJson
+	}
+	if run.evtJson == nil {
+		result.Failures = append(result.Failures, "No event definitions provided")
+		return result
+	}
+	run.fMgr = faultMgr.NewFaultManagerWithHandles(runner.logger, run.db, run.pub, run.clock)
+	for _, err := range run.fMgr.InitEventMaps(run.evtJson) {
+		result.Failures = append(result.Failures, fmt.Sprint("Event definitions: ", err))
+	}
+	doneCh := make(chan bool)
+	go servePauseRequests(run.fMgr, doneCh)
+	defer close(doneCh)
+
+	for idx, step := range sc.Steps {
+		for _, err := range run.executeStep(&step) {
+			result.Failures = append(result.Failures, fmt.Sprintf("Step %d: %s", idx+1, err))
+		}
+	}
+	return result
+}
+
+func (run *scenarioRun) executeStep(step *Step) []error {
+	var err error
+	switch {
+	case step.Event != nil:
+		err = run.raiseEvent(step.Event)
+	case step.Advance != "":
+		var d time.Duration
+		d, err = time.ParseDuration(step.Advance)
+		if err == nil {
+			run.clock.Advance(d)
+		}
+	case step.Action != nil:
+		err = run.executeAction(step.Action)
+	case step.ProcessQueued:
+		err = run.processQueuedEvents()
+	case step.Expect != nil:
+		return run.checkExpectation(step.Expect)
+	default:
+		err = errors.New("Empty step")
+	}
+	if err != nil {
+		return []error{err}
+	}
+	// Fire the timers armed with no delay
+	run.clock.Advance(0)
+	return nil
+}
+
+func (run *scenarioRun) raiseEvent(evtStep *EventStep) error {
+	for _, daemon := range run.evtJson.DaemonEvents {
+		if daemon.DaemonName != evtStep.OwnerName {
+			continue
+		}
+		for _, evtDef := range daemon.EventList {
+			if evtDef.EventName != evtStep.EventName {
+				continue
+			}
+			evt := eventUtils.Event{
+				OwnerId:     daemon.DaemonId,
+				OwnerName:   daemon.DaemonName,
+				EvtId:       evtDef.EventId,
+				EventName:   evtDef.EventName,
+				TimeStamp:   run.clock.Now(),
+				Description: evtStep.Description,
+				SrcObjName:  evtDef.SrcObjName,
+				SrcObjKey:   evtStep.SrcObjKey,
+			}
+			if evt.Description == "" {
+				evt.Description = evtDef.Description
+			}
+			msg, err := json.Marshal(evt)
+			if err != nil {
+				return err
+			}
+			if !evtStep.Queued {
+				return run.fMgr.ProcessEvent(daemon.DaemonName, msg)
+			}
+			return run.queueEvent(daemon.DaemonName, msg, evtStep.Count)
+		}
+	}
+	return errors.New(fmt.Sprintln("Unknown event", evtStep.OwnerName, evtStep.EventName))
+}
+
+func (run *scenarioRun) queueEvent(owner string, msg []byte, count int) error {
+	if count == 0 {
+		count = 1
+	}
+	for idx := 0; idx < count; idx++ {
+		// Nothing drains EventCh while the step runs
+		if len(run.fMgr.EventCh) == cap(run.fMgr.EventCh) && (run.policy == "" || run.policy == faultMgr.OVERFLOW_BLOCK) {
+			return errors.New("EventCh is full, queuing the event would block")
+		}
+		// Dropped events are checked through EventsDropped
+		run.fMgr.EnqueueEvent(owner, msg)
+	}
+	return nil
+}
+
+func (run *scenarioRun) executeAction(action *ActionStep) error {
+	var err error
+	switch action.Name {
+	case FAULT_ENABLE_ACTION:
+		_, err = run.fMgr.FaultEnableAction(&objects.FaultEnable{
+			OwnerName: action.OwnerName,
+			EventName: action.EventName,
+			Enable:    action.Enable,
+		})
+	case FAULT_CLEAR_ACTION:
+		_, err = run.fMgr.FaultClearAction(&objects.FaultClear{
+			OwnerName:  action.OwnerName,
+			EventName:  action.EventName,
+			SrcObjUUID: action.SrcObjUUID,
+		})
+	case FAULT_INJECT_ACTION:
+		var srcObjKey []byte
+		srcObjKey, err = json.Marshal(action.SrcObjKey)
+		if err != nil {
+			return err
+		}
+		_, err = run.fMgr.FaultInjectAction(&objects.FaultInject{
+			OwnerName:   action.OwnerName,
+			EventName:   action.EventName,
+			SrcObjKey:   string(srcObjKey),
+			Description: action.Description,
+		})
+		if err == nil {
+			err = run.processQueuedEvents()
+		}
+	case ALARM_SHELVE_ACTION:
+		_, err = run.fMgr.AlarmShelveAction(&objects.AlarmShelve{
+			OwnerName:  action.OwnerName,
+			EventName:  action.EventName,
+			SrcObjUUID: action.SrcObjUUID,
+			Duration:   action.Duration,
+		})
+	case FMGR_GLOBAL_ACTION:
+		_, err = run.fMgr.CreateFMgrGlobal(&objects.FMgrGlobal{
+			EventOverflowPolicy: action.Policy,
+		})
+		if err == nil {
+			run.policy = action.Policy
+		}
+	case MAINTENANCE_ACTION:
+		var srcObjKey []byte
+		if action.SrcObjKey != nil {
+			srcObjKey, err = json.Marshal(action.SrcObjKey)
+			if err != nil {
+				return err
+			}
+		}
+		now := run.clock.Now()
+		_, err = run.fMgr.CreateMaintenanceWindow(&objects.MaintenanceWindow{
+			Name:      SCENARIO_MAINTENANCE_WINDOW,
+			OwnerName: action.OwnerName,
+			SrcObjKey: string(srcObjKey),
+			StartTime: now.Format(time.RFC3339),
+			EndTime:   now.Add(time.Duration(action.Duration) * time.Second).Format(time.RFC3339),
+		})
+	case DAEMON_STATUS_ACTION:
+		var status sysdCommonDefs.SRDaemonStatus
+		status, err = getDaemonStatus(action.Status)
+		if err != nil {
+			return err
+		}
+		err = run.fMgr.ProcessDaemonStatus(sysdCommonDefs.DaemonStatus{
+			Name:   action.Daemon,
+			Status: status,
+		})
+		if err == nil {
+			err = run.processQueuedEvents()
+		}
+	default:
+		err = errors.New(fmt.Sprintln("Unknown action", action.Name))
+	}
+	return err
+}
+
+func getDaemonStatus(statusStr string) (sysdCommonDefs.SRDaemonStatus, error) {
+	for _, status := range []sysdCommonDefs.SRDaemonStatus{sysdCommonDefs.UP, sysdCommonDefs.STARTING,
+		sysdCommonDefs.RESTARTING, sysdCommonDefs.STOPPED} {
+		if sysdCommonDefs.ConvertDaemonStateCodeToString(status) == statusStr {
+			return status, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintln("Unknown daemon status", statusStr))
+}
+
+// processQueuedEvents processes the events queued on EventCh by the actions,
+// as the event processor of fault manager is not running
+func (run *scenarioRun) processQueuedEvents() error {
+	for {
+		select {
+		case msg := <-run.fMgr.EventCh:
+			if err := run.fMgr.ProcessEvent(msg.Owner, msg.Data); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func checkCount(name string, expected *int, actual int) error {
+	if expected == nil || *expected == actual {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("%s: expected %d, got %d", name, *expected, actual))
+}
+
+func (run *scenarioRun) checkExpectation(expect *Expectation) (errList []error) {
+	cursor := &objects.StateCursor{
+		SeqNumber: -1,
+		Direction: objects.CURSOR_FORWARD,
+		Count:     math.MaxInt32,
+	}
+	faults, err := run.fMgr.GetFaultStateByCursor(cursor, nil)
+	if err != nil {
+		return []error{err}
+	}
+	alarms, err := run.fMgr.GetAlarmStateByCursor(cursor, nil)
+	if err != nil {
+		return []error{err}
+	}
+	activeFaults, err := run.fMgr.GetBulkActiveFault(0, faults.Count)
+	if err != nil {
+		return []error{err}
+	}
+	activeAlarms, err := run.fMgr.GetBulkActiveAlarm(0, alarms.Count)
+	if err != nil {
+		return []error{err}
+	}
+	var resolvedFaults, resolvedAlarms, injectedFaults, injectedAlarms int
+	for _, fault := range faults.List {
+		if fault.ResolutionTime != "N/A" {
+			resolvedFaults++
+		}
+		if fault.Injected {
+			injectedFaults++
+		}
+	}
+	for _, alarm := range alarms.List {
+		if alarm.ResolutionTime != "N/A" {
+			resolvedAlarms++
+		}
+		if alarm.Injected {
+			injectedAlarms++
+		}
+	}
+	for _, err := range []error{
+		checkCount("ActiveFaults", expect.ActiveFaults, activeFaults.Count),
+		checkCount("ActiveAlarms", expect.ActiveAlarms, activeAlarms.Count),
+		checkCount("Faults", expect.Faults, faults.Count),
+		checkCount("Alarms", expect.Alarms, alarms.Count),
+		checkCount("ResolvedFaults", expect.ResolvedFaults, resolvedFaults),
+		checkCount("ResolvedAlarms", expect.ResolvedAlarms, resolvedAlarms),
+		checkCount("InjectedFaults", expect.InjectedFaults, injectedFaults),
+		checkCount("InjectedAlarms", expect.InjectedAlarms, injectedAlarms),
+	} {
+		if err != nil {
+			errList = append(errList, err)
+		}
+	}
+	for owner, count := range expect.EventsProcessed {
+		expected := count
+		if err := checkCount("Events processed for "+owner, &expected, int(run.getIngestState(owner).Processed)); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	for owner, count := range expect.EventsDropped {
+		expected := count
+		if err := checkCount("Events dropped for "+owner, &expected, int(run.getIngestState(owner).Dropped)); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	published := run.pub.ChannelCount()
+	for channel, count := range expect.Published {
+		expected := count
+		if err := checkCount("Published on "+channel, &expected, published[channel]); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return errList
+}
+
+func (run *scenarioRun) getIngestState(owner string) objects.EventIngestState {
+	state, err := run.fMgr.GetEventIngestState(owner)
+	if err != nil {
+		return objects.EventIngestState{OwnerName: owner}
+	}
+	return *state
+}
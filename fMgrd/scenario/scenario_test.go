@@ -0,0 +1,46 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package scenario
+
+import (
+	"testing"
+	"utils/logging"
+)
+
+func TestBuiltinScenarios(t *testing.T) {
+	logger, err := logging.NewLogger("fMgrd", "FMGRD", false)
+	if err != nil {
+		t.Fatal("Error creating logger:", err)
+	}
+	runner := NewRunner(logger, BuiltinEvents)
+	for idx := range BuiltinScenarios {
+		sc := &BuiltinScenarios[idx]
+		t.Run(sc.Name, func(t *testing.T) {
+			result := runner.Run(sc)
+			for _, failure := range result.Failures {
+				t.Error(failure)
+			}
+		})
+	}
+}
@@ -27,7 +27,82 @@ import (
 	"infra/fMgrd/objects"
 )
 
-func (svr *FMGRServer) getBulkAlarmState(fromIdx int, count int) (*objects.AlarmStateGetInfo, error) {
-	retObj, err := svr.fMgr.GetBulkAlarmState(fromIdx, count)
+func (svr *FMGRServer) getBulkAlarmState(fromIdx int, count int, filter *objects.StateFilter) (*objects.AlarmStateGetInfo, error) {
+	retObj, err := svr.fMgr.GetBulkAlarmState(fromIdx, count, filter)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getAlarmStateByCursor(cursor *objects.StateCursor, filter *objects.StateFilter) (*objects.AlarmStateCursorInfo, error) {
+	retObj, err := svr.fMgr.GetAlarmStateByCursor(cursor, filter)
+	return retObj, err
+}
+
+func (svr *FMGRServer) alarmAckAction(config *objects.AlarmAck) (bool, error) {
+	retObj, err := svr.fMgr.AlarmAckAction(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) alarmShelveAction(config *objects.AlarmShelve) (bool, error) {
+	retObj, err := svr.fMgr.AlarmShelveAction(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getAlarmState(args *GetStateInArgs) (*objects.AlarmState, error) {
+	retObj, err := svr.fMgr.GetAlarmState(args.OwnerId, args.EventId, args.OwnerName, args.EventName, args.SrcObjName, args.SrcObjUUID)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getBulkActiveAlarm(fromIdx int, count int) (*objects.ActiveAlarmGetInfo, error) {
+	retObj, err := svr.fMgr.GetBulkActiveAlarm(fromIdx, count)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getActiveAlarm(args *GetActiveInArgs) (*objects.ActiveAlarm, error) {
+	retObj, err := svr.fMgr.GetActiveAlarm(args.OwnerName, args.EventName, args.SrcObjUUID)
+	return retObj, err
+}
+
+func (svr *FMGRServer) createAlarmEscalation(config *objects.AlarmEscalation) (bool, error) {
+	retObj, err := svr.fMgr.CreateAlarmEscalation(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) updateAlarmEscalation(oldCfg, newCfg *objects.AlarmEscalation, attrset []bool) (bool, error) {
+	retObj, err := svr.fMgr.UpdateAlarmEscalation(oldCfg, newCfg, attrset)
+	return retObj, err
+}
+
+func (svr *FMGRServer) deleteAlarmEscalation(config *objects.AlarmEscalation) (bool, error) {
+	retObj, err := svr.fMgr.DeleteAlarmEscalation(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) createSnmpTrapReceiver(config *objects.SnmpTrapReceiver) (bool, error) {
+	retObj, err := svr.fMgr.CreateSnmpTrapReceiver(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) updateSnmpTrapReceiver(oldCfg, newCfg *objects.SnmpTrapReceiver, attrset []bool) (bool, error) {
+	retObj, err := svr.fMgr.UpdateSnmpTrapReceiver(oldCfg, newCfg, attrset)
+	return retObj, err
+}
+
+func (svr *FMGRServer) deleteSnmpTrapReceiver(config *objects.SnmpTrapReceiver) (bool, error) {
+	retObj, err := svr.fMgr.DeleteSnmpTrapReceiver(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) createSyslogServer(config *objects.SyslogServer) (bool, error) {
+	retObj, err := svr.fMgr.CreateSyslogServer(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) updateSyslogServer(oldCfg, newCfg *objects.SyslogServer, attrset []bool) (bool, error) {
+	retObj, err := svr.fMgr.UpdateSyslogServer(oldCfg, newCfg, attrset)
+	return retObj, err
+}
+
+func (svr *FMGRServer) deleteSyslogServer(config *objects.SyslogServer) (bool, error) {
+	retObj, err := svr.fMgr.DeleteSyslogServer(config)
 	return retObj, err
 }
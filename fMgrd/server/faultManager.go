@@ -27,8 +27,13 @@ import (
 	"infra/fMgrd/objects"
 )
 
-func (svr *FMGRServer) getBulkFaultState(fromIdx int, count int) (*objects.FaultStateGetInfo, error) {
-	retObj, err := svr.fMgr.GetBulkFaultState(fromIdx, count)
+func (svr *FMGRServer) getBulkFaultState(fromIdx int, count int, filter *objects.StateFilter) (*objects.FaultStateGetInfo, error) {
+	retObj, err := svr.fMgr.GetBulkFaultState(fromIdx, count, filter)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getFaultStateByCursor(cursor *objects.StateCursor, filter *objects.StateFilter) (*objects.FaultStateCursorInfo, error) {
+	retObj, err := svr.fMgr.GetFaultStateByCursor(cursor, filter)
 	return retObj, err
 }
 
@@ -41,3 +46,107 @@ func (svr *FMGRServer) faultClearAction(config *objects.FaultClear) (bool, error
 	retObj, err := svr.fMgr.FaultClearAction(config)
 	return retObj, err
 }
+
+func (svr *FMGRServer) createFMgrGlobal(config *objects.FMgrGlobal) (bool, error) {
+	retObj, err := svr.fMgr.CreateFMgrGlobal(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) updateFMgrGlobal(oldCfg, newCfg *objects.FMgrGlobal, attrset []bool) (bool, error) {
+	retObj, err := svr.fMgr.UpdateFMgrGlobal(oldCfg, newCfg, attrset)
+	return retObj, err
+}
+
+func (svr *FMGRServer) createEventHoldTime(config *objects.EventHoldTime) (bool, error) {
+	retObj, err := svr.fMgr.CreateEventHoldTime(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) updateEventHoldTime(oldCfg, newCfg *objects.EventHoldTime, attrset []bool) (bool, error) {
+	retObj, err := svr.fMgr.UpdateEventHoldTime(oldCfg, newCfg, attrset)
+	return retObj, err
+}
+
+func (svr *FMGRServer) deleteEventHoldTime(config *objects.EventHoldTime) (bool, error) {
+	retObj, err := svr.fMgr.DeleteEventHoldTime(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getFaultState(args *GetStateInArgs) (*objects.FaultState, error) {
+	retObj, err := svr.fMgr.GetFaultState(args.OwnerId, args.EventId, args.OwnerName, args.EventName, args.SrcObjName, args.SrcObjUUID)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getBulkActiveFault(fromIdx int, count int) (*objects.ActiveFaultGetInfo, error) {
+	retObj, err := svr.fMgr.GetBulkActiveFault(fromIdx, count)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getActiveFault(args *GetActiveInArgs) (*objects.ActiveFault, error) {
+	retObj, err := svr.fMgr.GetActiveFault(args.OwnerName, args.EventName, args.SrcObjUUID)
+	return retObj, err
+}
+
+func (svr *FMGRServer) eventsReloadAction(config *objects.EventsReload) (bool, error) {
+	daemonList := svr.fMgr.GetDaemonList()
+	retObj, err := svr.fMgr.EventsReloadAction(config)
+	if err == nil {
+		svr.updateSubscriber(daemonList)
+	}
+	return retObj, err
+}
+
+func (svr *FMGRServer) getEventsReloadState(vrf string) (*objects.EventsReloadState, error) {
+	retObj, err := svr.fMgr.GetEventsReloadState(vrf)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getAlarmSummary(vrf string) (*objects.AlarmSummary, error) {
+	retObj, err := svr.fMgr.GetAlarmSummary(vrf)
+	return retObj, err
+}
+
+func (svr *FMGRServer) faultInjectAction(config *objects.FaultInject) (bool, error) {
+	retObj, err := svr.fMgr.FaultInjectAction(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) historyExportAction(config *objects.HistoryExport) (bool, error) {
+	retObj, err := svr.fMgr.HistoryExportAction(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getHistoryExportState(fileName string) (*objects.HistoryExportState, error) {
+	retObj, err := svr.fMgr.GetHistoryExportState(fileName)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getBulkHistoryExportState(fromIdx, count int) (*objects.HistoryExportStateGetInfo, error) {
+	retObj, err := svr.fMgr.GetBulkHistoryExportState(fromIdx, count)
+	return retObj, err
+}
+
+func (svr *FMGRServer) createMaintenanceWindow(config *objects.MaintenanceWindow) (bool, error) {
+	retObj, err := svr.fMgr.CreateMaintenanceWindow(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) updateMaintenanceWindow(oldCfg, newCfg *objects.MaintenanceWindow, attrset []bool) (bool, error) {
+	retObj, err := svr.fMgr.UpdateMaintenanceWindow(oldCfg, newCfg, attrset)
+	return retObj, err
+}
+
+func (svr *FMGRServer) deleteMaintenanceWindow(config *objects.MaintenanceWindow) (bool, error) {
+	retObj, err := svr.fMgr.DeleteMaintenanceWindow(config)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getEventIngestState(ownerName string) (*objects.EventIngestState, error) {
+	retObj, err := svr.fMgr.GetEventIngestState(ownerName)
+	return retObj, err
+}
+
+func (svr *FMGRServer) getBulkEventIngestState(fromIdx, count int) (*objects.EventIngestStateGetInfo, error) {
+	retObj, err := svr.fMgr.GetBulkEventIngestState(fromIdx, count)
+	return retObj, err
+}
@@ -26,16 +26,18 @@ package server
 import (
 	"errors"
 	"fmt"
-	"github.com/garyburd/redigo/redis"
+	"infra/fMgrd/eventBus"
 	"infra/fMgrd/faultMgr"
-	"time"
+	"infra/fMgrd/objects"
+	"os"
+	"os/signal"
+	"syscall"
 	"utils/logging"
 )
 
 type FMGRServer struct {
 	Logger    logging.LoggerIntf
-	dbHdl     redis.Conn
-	subHdl    redis.PubSubConn
+	bus       eventBus.EventBus
 	fMgr      *faultMgr.FaultManager
 	InitDone  chan bool
 	ReqChan   chan *ServerRequest
@@ -51,46 +53,21 @@ func NewFMGRServer(logger *logging.Writer) *FMGRServer {
 	return fMgrServer
 }
 
-func (server *FMGRServer) dial() (redis.Conn, error) {
-	retryCount := 0
-	ticker := time.NewTicker(2 * time.Second)
-	for _ = range ticker.C {
-		retryCount += 1
-		dbHdl, err := redis.Dial("tcp", ":6379")
-		if err != nil {
-			if retryCount%100 == 0 {
-				server.Logger.Err(fmt.Sprintln("Failed to dail out to Redis server. Retrying connection. Num of retries = ", retryCount))
-			}
-		} else {
-			return dbHdl, nil
-		}
-	}
-	err := errors.New("Error opening db handler")
-	return nil, err
-}
-
 func (server *FMGRServer) Subscriber() {
 	for {
-		switch n := server.subHdl.Receive().(type) {
-		case redis.Message:
-			server.fMgr.EventCh <- n.Data
-		case redis.Subscription:
-			if n.Count == 0 {
-				server.Logger.Err("Empty data Received")
-			}
-		case error:
-			server.Logger.Err(fmt.Sprintf("error: %v\n", n))
+		msg, err := server.bus.Receive()
+		if err != nil {
+			server.Logger.Err(fmt.Sprintf("error: %v\n", err))
 			return
 		}
+		server.fMgr.EnqueueEvent(msg.Channel, msg.Data)
 	}
-	server.subHdl.Unsubscribe()
-	//server.subHdl.PUnsubscribe()
 }
 
 func (server *FMGRServer) InitSubscriber() error {
 	var errMsg string
-	for _, daemon := range server.fMgr.DaemonList {
-		err := server.subHdl.Subscribe(daemon)
+	for _, daemon := range server.fMgr.GetDaemonList() {
+		err := server.bus.Subscribe(daemon)
 		if err != nil {
 			errMsg = fmt.Sprintf("%s : %s", errMsg, err)
 		}
@@ -103,20 +80,52 @@ func (server *FMGRServer) InitSubscriber() error {
 	return errors.New(fmt.Sprintln("Error Initializing Subscriber:", errMsg))
 }
 
+// updateSubscriber subscribes to the daemons added to events.json and
+// unsubscribes from the ones removed from it
+func (server *FMGRServer) updateSubscriber(oldDaemonList []string) {
+	oldDaemons := make(map[string]bool)
+	for _, daemon := range oldDaemonList {
+		oldDaemons[daemon] = true
+	}
+	for _, daemon := range server.fMgr.GetDaemonList() {
+		if _, exist := oldDaemons[daemon]; exist {
+			delete(oldDaemons, daemon)
+			continue
+		}
+		err := server.bus.Subscribe(daemon)
+		if err != nil {
+			server.Logger.Err(fmt.Sprintln("Error subscribing to", daemon, err))
+		}
+	}
+	for daemon, _ := range oldDaemons {
+		err := server.bus.Unsubscribe(daemon)
+		if err != nil {
+			server.Logger.Err(fmt.Sprintln("Error unsubscribing from", daemon, err))
+		}
+	}
+}
+
 func (server *FMGRServer) InitServer() error {
-	server.fMgr = faultMgr.NewFaultManager(server.Logger)
-	err := server.fMgr.InitFaultManager()
+	busCfg, err := eventBus.LoadEventBusConfig(eventBus.EVENT_BUS_CONFIG_FILE)
+	if err != nil {
+		server.Logger.Err(fmt.Sprintln("Error loading event bus config, using defaults:", err))
+	}
+	server.bus, err = eventBus.NewEventBus(server.Logger, busCfg)
+	if err != nil {
+		server.Logger.Err(fmt.Sprintln(err))
+		return err
+	}
+	server.fMgr = faultMgr.NewFaultManager(server.Logger, server.bus)
+	err = server.fMgr.InitFaultManager()
 	if err != nil {
 		server.Logger.Err(fmt.Sprintln(err))
 		return err
 	}
-	server.dbHdl, err = server.dial()
+	err = server.bus.Connect()
 	if err != nil {
 		server.Logger.Err(fmt.Sprintln(err))
 		return err
 	}
-	//defer server.dbHdl.Close()
-	server.subHdl = redis.PubSubConn{Conn: server.dbHdl}
 
 	err = server.InitSubscriber()
 	if err != nil {
@@ -124,6 +133,7 @@ func (server *FMGRServer) InitServer() error {
 		return err
 	}
 	go server.Subscriber()
+	go server.ListenToSysdUpdates()
 	return err
 }
 
@@ -133,13 +143,49 @@ func (server *FMGRServer) handleRPCRequest(req *ServerRequest) {
 	case GET_BULK_FAULT_STATE:
 		var retObj GetBulkFaultStateOutArgs
 		if val, ok := req.Data.(*GetBulkInArgs); ok {
-			retObj.BulkInfo, retObj.Err = server.getBulkFaultState(val.FromIdx, val.Count)
+			retObj.BulkInfo, retObj.Err = server.getBulkFaultState(val.FromIdx, val.Count, val.Filter)
 		}
 		server.ReplyChan <- interface{}(&retObj)
 	case GET_BULK_ALARM_STATE:
 		var retObj GetBulkAlarmStateOutArgs
 		if val, ok := req.Data.(*GetBulkInArgs); ok {
-			retObj.BulkInfo, retObj.Err = server.getBulkAlarmState(val.FromIdx, val.Count)
+			retObj.BulkInfo, retObj.Err = server.getBulkAlarmState(val.FromIdx, val.Count, val.Filter)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_FAULT_STATE:
+		var retObj GetFaultStateOutArgs
+		if val, ok := req.Data.(*GetStateInArgs); ok {
+			retObj.Obj, retObj.Err = server.getFaultState(val)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_ALARM_STATE:
+		var retObj GetAlarmStateOutArgs
+		if val, ok := req.Data.(*GetStateInArgs); ok {
+			retObj.Obj, retObj.Err = server.getAlarmState(val)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_BULK_ACTIVE_FAULT:
+		var retObj GetBulkActiveFaultOutArgs
+		if val, ok := req.Data.(*GetBulkInArgs); ok {
+			retObj.BulkInfo, retObj.Err = server.getBulkActiveFault(val.FromIdx, val.Count)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_BULK_ACTIVE_ALARM:
+		var retObj GetBulkActiveAlarmOutArgs
+		if val, ok := req.Data.(*GetBulkInArgs); ok {
+			retObj.BulkInfo, retObj.Err = server.getBulkActiveAlarm(val.FromIdx, val.Count)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_ACTIVE_FAULT:
+		var retObj GetActiveFaultOutArgs
+		if val, ok := req.Data.(*GetActiveInArgs); ok {
+			retObj.Obj, retObj.Err = server.getActiveFault(val)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_ACTIVE_ALARM:
+		var retObj GetActiveAlarmOutArgs
+		if val, ok := req.Data.(*GetActiveInArgs); ok {
+			retObj.Obj, retObj.Err = server.getActiveAlarm(val)
 		}
 		server.ReplyChan <- interface{}(&retObj)
 	case FAULT_ENABLE_ACTION:
@@ -154,6 +200,186 @@ func (server *FMGRServer) handleRPCRequest(req *ServerRequest) {
 			retObj.RetVal, retObj.Err = server.faultClearAction(val.Config)
 		}
 		server.ReplyChan <- interface{}(&retObj)
+	case ALARM_ACK_ACTION:
+		var retObj AlarmAckActionOutArgs
+		if val, ok := req.Data.(*AlarmAckActionInArgs); ok {
+			retObj.RetVal, retObj.Err = server.alarmAckAction(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case ALARM_SHELVE_ACTION:
+		var retObj AlarmShelveActionOutArgs
+		if val, ok := req.Data.(*AlarmShelveActionInArgs); ok {
+			retObj.RetVal, retObj.Err = server.alarmShelveAction(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case CREATE_FMGR_GLOBAL:
+		var retObj FMgrGlobalOutArgs
+		if val, ok := req.Data.(*CreateFMgrGlobalInArgs); ok {
+			retObj.RetVal, retObj.Err = server.createFMgrGlobal(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case UPDATE_FMGR_GLOBAL:
+		var retObj FMgrGlobalOutArgs
+		if val, ok := req.Data.(*UpdateFMgrGlobalInArgs); ok {
+			retObj.RetVal, retObj.Err = server.updateFMgrGlobal(val.OldCfg, val.NewCfg, val.AttrSet)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case CREATE_EVENT_HOLD_TIME:
+		var retObj EventHoldTimeOutArgs
+		if val, ok := req.Data.(*CreateEventHoldTimeInArgs); ok {
+			retObj.RetVal, retObj.Err = server.createEventHoldTime(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case UPDATE_EVENT_HOLD_TIME:
+		var retObj EventHoldTimeOutArgs
+		if val, ok := req.Data.(*UpdateEventHoldTimeInArgs); ok {
+			retObj.RetVal, retObj.Err = server.updateEventHoldTime(val.OldCfg, val.NewCfg, val.AttrSet)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case DELETE_EVENT_HOLD_TIME:
+		var retObj EventHoldTimeOutArgs
+		if val, ok := req.Data.(*DeleteEventHoldTimeInArgs); ok {
+			retObj.RetVal, retObj.Err = server.deleteEventHoldTime(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case CREATE_ALARM_ESCALATION:
+		var retObj AlarmEscalationOutArgs
+		if val, ok := req.Data.(*CreateAlarmEscalationInArgs); ok {
+			retObj.RetVal, retObj.Err = server.createAlarmEscalation(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case UPDATE_ALARM_ESCALATION:
+		var retObj AlarmEscalationOutArgs
+		if val, ok := req.Data.(*UpdateAlarmEscalationInArgs); ok {
+			retObj.RetVal, retObj.Err = server.updateAlarmEscalation(val.OldCfg, val.NewCfg, val.AttrSet)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case DELETE_ALARM_ESCALATION:
+		var retObj AlarmEscalationOutArgs
+		if val, ok := req.Data.(*DeleteAlarmEscalationInArgs); ok {
+			retObj.RetVal, retObj.Err = server.deleteAlarmEscalation(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case CREATE_SNMP_TRAP_RECEIVER:
+		var retObj SnmpTrapReceiverOutArgs
+		if val, ok := req.Data.(*CreateSnmpTrapReceiverInArgs); ok {
+			retObj.RetVal, retObj.Err = server.createSnmpTrapReceiver(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case UPDATE_SNMP_TRAP_RECEIVER:
+		var retObj SnmpTrapReceiverOutArgs
+		if val, ok := req.Data.(*UpdateSnmpTrapReceiverInArgs); ok {
+			retObj.RetVal, retObj.Err = server.updateSnmpTrapReceiver(val.OldCfg, val.NewCfg, val.AttrSet)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case DELETE_SNMP_TRAP_RECEIVER:
+		var retObj SnmpTrapReceiverOutArgs
+		if val, ok := req.Data.(*DeleteSnmpTrapReceiverInArgs); ok {
+			retObj.RetVal, retObj.Err = server.deleteSnmpTrapReceiver(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case CREATE_SYSLOG_SERVER:
+		var retObj SyslogServerOutArgs
+		if val, ok := req.Data.(*CreateSyslogServerInArgs); ok {
+			retObj.RetVal, retObj.Err = server.createSyslogServer(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case UPDATE_SYSLOG_SERVER:
+		var retObj SyslogServerOutArgs
+		if val, ok := req.Data.(*UpdateSyslogServerInArgs); ok {
+			retObj.RetVal, retObj.Err = server.updateSyslogServer(val.OldCfg, val.NewCfg, val.AttrSet)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case DELETE_SYSLOG_SERVER:
+		var retObj SyslogServerOutArgs
+		if val, ok := req.Data.(*DeleteSyslogServerInArgs); ok {
+			retObj.RetVal, retObj.Err = server.deleteSyslogServer(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case EVENTS_RELOAD_ACTION:
+		var retObj EventsReloadActionOutArgs
+		if val, ok := req.Data.(*EventsReloadActionInArgs); ok {
+			retObj.RetVal, retObj.Err = server.eventsReloadAction(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_EVENTS_RELOAD_STATE:
+		var retObj GetEventsReloadStateOutArgs
+		if val, ok := req.Data.(*GetEventsReloadStateInArgs); ok {
+			retObj.Obj, retObj.Err = server.getEventsReloadState(val.Vrf)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_ALARM_SUMMARY:
+		var retObj GetAlarmSummaryOutArgs
+		if val, ok := req.Data.(*GetAlarmSummaryInArgs); ok {
+			retObj.Obj, retObj.Err = server.getAlarmSummary(val.Vrf)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_FAULT_STATE_BY_CURSOR:
+		var retObj GetFaultStateByCursorOutArgs
+		if val, ok := req.Data.(*GetByCursorInArgs); ok {
+			retObj.CursorInfo, retObj.Err = server.getFaultStateByCursor(val.Cursor, val.Filter)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_ALARM_STATE_BY_CURSOR:
+		var retObj GetAlarmStateByCursorOutArgs
+		if val, ok := req.Data.(*GetByCursorInArgs); ok {
+			retObj.CursorInfo, retObj.Err = server.getAlarmStateByCursor(val.Cursor, val.Filter)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case FAULT_INJECT_ACTION:
+		var retObj FaultInjectActionOutArgs
+		if val, ok := req.Data.(*FaultInjectActionInArgs); ok {
+			retObj.RetVal, retObj.Err = server.faultInjectAction(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case HISTORY_EXPORT_ACTION:
+		var retObj HistoryExportActionOutArgs
+		if val, ok := req.Data.(*HistoryExportActionInArgs); ok {
+			retObj.RetVal, retObj.Err = server.historyExportAction(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_HISTORY_EXPORT_STATE:
+		var retObj GetHistoryExportStateOutArgs
+		if val, ok := req.Data.(*GetHistoryExportStateInArgs); ok {
+			retObj.Obj, retObj.Err = server.getHistoryExportState(val.FileName)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_BULK_HISTORY_EXPORT_STATE:
+		var retObj GetBulkHistoryExportStateOutArgs
+		if val, ok := req.Data.(*GetBulkInArgs); ok {
+			retObj.BulkInfo, retObj.Err = server.getBulkHistoryExportState(val.FromIdx, val.Count)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case CREATE_MAINTENANCE_WINDOW:
+		var retObj MaintenanceWindowOutArgs
+		if val, ok := req.Data.(*CreateMaintenanceWindowInArgs); ok {
+			retObj.RetVal, retObj.Err = server.createMaintenanceWindow(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case UPDATE_MAINTENANCE_WINDOW:
+		var retObj MaintenanceWindowOutArgs
+		if val, ok := req.Data.(*UpdateMaintenanceWindowInArgs); ok {
+			retObj.RetVal, retObj.Err = server.updateMaintenanceWindow(val.OldCfg, val.NewCfg, val.AttrSet)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case DELETE_MAINTENANCE_WINDOW:
+		var retObj MaintenanceWindowOutArgs
+		if val, ok := req.Data.(*DeleteMaintenanceWindowInArgs); ok {
+			retObj.RetVal, retObj.Err = server.deleteMaintenanceWindow(val.Config)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_EVENT_INGEST_STATE:
+		var retObj GetEventIngestStateOutArgs
+		if val, ok := req.Data.(*GetEventIngestStateInArgs); ok {
+			retObj.Obj, retObj.Err = server.getEventIngestState(val.OwnerName)
+		}
+		server.ReplyChan <- interface{}(&retObj)
+	case GET_BULK_EVENT_INGEST_STATE:
+		var retObj GetBulkEventIngestStateOutArgs
+		if val, ok := req.Data.(*GetBulkInArgs); ok {
+			retObj.BulkInfo, retObj.Err = server.getBulkEventIngestState(val.FromIdx, val.Count)
+		}
+		server.ReplyChan <- interface{}(&retObj)
 	default:
 		server.Logger.Err(fmt.Sprintln("Error: Server received unrecognized request - ", req.Op))
 	}
@@ -162,11 +388,16 @@ func (server *FMGRServer) handleRPCRequest(req *ServerRequest) {
 func (server *FMGRServer) StartServer() {
 	server.InitServer()
 	server.InitDone <- true
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
 	for {
 		select {
 		case req := <-server.ReqChan:
 			server.Logger.Info(fmt.Sprintln("Server request received - ", *req))
 			server.handleRPCRequest(req)
+		case _ = <-sigChan:
+			server.Logger.Info("Received SIGHUP signal, reloading events.json")
+			server.eventsReloadAction(&objects.EventsReload{})
 		}
 	}
 }
@@ -34,6 +34,42 @@ const (
 	GET_BULK_ALARM_STATE
 	FAULT_ENABLE_ACTION
 	FAULT_CLEAR_ACTION
+	ALARM_ACK_ACTION
+	ALARM_SHELVE_ACTION
+	CREATE_FMGR_GLOBAL
+	UPDATE_FMGR_GLOBAL
+	CREATE_EVENT_HOLD_TIME
+	UPDATE_EVENT_HOLD_TIME
+	DELETE_EVENT_HOLD_TIME
+	GET_FAULT_STATE
+	GET_ALARM_STATE
+	GET_BULK_ACTIVE_FAULT
+	GET_BULK_ACTIVE_ALARM
+	GET_ACTIVE_FAULT
+	GET_ACTIVE_ALARM
+	CREATE_ALARM_ESCALATION
+	UPDATE_ALARM_ESCALATION
+	DELETE_ALARM_ESCALATION
+	CREATE_SNMP_TRAP_RECEIVER
+	UPDATE_SNMP_TRAP_RECEIVER
+	DELETE_SNMP_TRAP_RECEIVER
+	CREATE_SYSLOG_SERVER
+	UPDATE_SYSLOG_SERVER
+	DELETE_SYSLOG_SERVER
+	EVENTS_RELOAD_ACTION
+	GET_EVENTS_RELOAD_STATE
+	GET_ALARM_SUMMARY
+	GET_FAULT_STATE_BY_CURSOR
+	GET_ALARM_STATE_BY_CURSOR
+	FAULT_INJECT_ACTION
+	HISTORY_EXPORT_ACTION
+	GET_HISTORY_EXPORT_STATE
+	GET_BULK_HISTORY_EXPORT_STATE
+	GET_EVENT_INGEST_STATE
+	GET_BULK_EVENT_INGEST_STATE
+	CREATE_MAINTENANCE_WINDOW
+	UPDATE_MAINTENANCE_WINDOW
+	DELETE_MAINTENANCE_WINDOW
 )
 
 type ServerRequest struct {
@@ -44,6 +80,26 @@ type ServerRequest struct {
 type GetBulkInArgs struct {
 	FromIdx int
 	Count   int
+	Filter  *objects.StateFilter
+}
+
+type GetStateInArgs struct {
+	OwnerId    int
+	EventId    int
+	OwnerName  string
+	EventName  string
+	SrcObjName string
+	SrcObjUUID string
+}
+
+type GetFaultStateOutArgs struct {
+	Obj *objects.FaultState
+	Err error
+}
+
+type GetAlarmStateOutArgs struct {
+	Obj *objects.AlarmState
+	Err error
 }
 
 type GetBulkFaultStateOutArgs struct {
@@ -73,3 +129,245 @@ type FaultClearActionOutArgs struct {
 	RetVal bool
 	Err    error
 }
+
+type AlarmAckActionInArgs struct {
+	Config *objects.AlarmAck
+}
+
+type AlarmAckActionOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type AlarmShelveActionInArgs struct {
+	Config *objects.AlarmShelve
+}
+
+type AlarmShelveActionOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type CreateFMgrGlobalInArgs struct {
+	Config *objects.FMgrGlobal
+}
+
+type UpdateFMgrGlobalInArgs struct {
+	OldCfg  *objects.FMgrGlobal
+	NewCfg  *objects.FMgrGlobal
+	AttrSet []bool
+}
+
+type FMgrGlobalOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type CreateEventHoldTimeInArgs struct {
+	Config *objects.EventHoldTime
+}
+
+type UpdateEventHoldTimeInArgs struct {
+	OldCfg  *objects.EventHoldTime
+	NewCfg  *objects.EventHoldTime
+	AttrSet []bool
+}
+
+type DeleteEventHoldTimeInArgs struct {
+	Config *objects.EventHoldTime
+}
+
+type EventHoldTimeOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type GetActiveInArgs struct {
+	OwnerName  string
+	EventName  string
+	SrcObjUUID string
+}
+
+type GetBulkActiveFaultOutArgs struct {
+	BulkInfo *objects.ActiveFaultGetInfo
+	Err      error
+}
+
+type GetBulkActiveAlarmOutArgs struct {
+	BulkInfo *objects.ActiveAlarmGetInfo
+	Err      error
+}
+
+type GetActiveFaultOutArgs struct {
+	Obj *objects.ActiveFault
+	Err error
+}
+
+type GetActiveAlarmOutArgs struct {
+	Obj *objects.ActiveAlarm
+	Err error
+}
+
+type CreateAlarmEscalationInArgs struct {
+	Config *objects.AlarmEscalation
+}
+
+type UpdateAlarmEscalationInArgs struct {
+	OldCfg  *objects.AlarmEscalation
+	NewCfg  *objects.AlarmEscalation
+	AttrSet []bool
+}
+
+type DeleteAlarmEscalationInArgs struct {
+	Config *objects.AlarmEscalation
+}
+
+type AlarmEscalationOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type CreateSnmpTrapReceiverInArgs struct {
+	Config *objects.SnmpTrapReceiver
+}
+
+type UpdateSnmpTrapReceiverInArgs struct {
+	OldCfg  *objects.SnmpTrapReceiver
+	NewCfg  *objects.SnmpTrapReceiver
+	AttrSet []bool
+}
+
+type DeleteSnmpTrapReceiverInArgs struct {
+	Config *objects.SnmpTrapReceiver
+}
+
+type SnmpTrapReceiverOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type CreateSyslogServerInArgs struct {
+	Config *objects.SyslogServer
+}
+
+type UpdateSyslogServerInArgs struct {
+	OldCfg  *objects.SyslogServer
+	NewCfg  *objects.SyslogServer
+	AttrSet []bool
+}
+
+type DeleteSyslogServerInArgs struct {
+	Config *objects.SyslogServer
+}
+
+type SyslogServerOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type EventsReloadActionInArgs struct {
+	Config *objects.EventsReload
+}
+
+type EventsReloadActionOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type GetEventsReloadStateInArgs struct {
+	Vrf string
+}
+
+type GetEventsReloadStateOutArgs struct {
+	Obj *objects.EventsReloadState
+	Err error
+}
+
+type GetAlarmSummaryInArgs struct {
+	Vrf string
+}
+
+type GetAlarmSummaryOutArgs struct {
+	Obj *objects.AlarmSummary
+	Err error
+}
+
+type GetByCursorInArgs struct {
+	Cursor *objects.StateCursor
+	Filter *objects.StateFilter
+}
+
+type GetFaultStateByCursorOutArgs struct {
+	CursorInfo *objects.FaultStateCursorInfo
+	Err        error
+}
+
+type GetAlarmStateByCursorOutArgs struct {
+	CursorInfo *objects.AlarmStateCursorInfo
+	Err        error
+}
+
+type FaultInjectActionInArgs struct {
+	Config *objects.FaultInject
+}
+
+type FaultInjectActionOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type HistoryExportActionInArgs struct {
+	Config *objects.HistoryExport
+}
+
+type HistoryExportActionOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type GetHistoryExportStateInArgs struct {
+	FileName string
+}
+
+type GetHistoryExportStateOutArgs struct {
+	Obj *objects.HistoryExportState
+	Err error
+}
+
+type GetBulkHistoryExportStateOutArgs struct {
+	BulkInfo *objects.HistoryExportStateGetInfo
+	Err      error
+}
+
+type CreateMaintenanceWindowInArgs struct {
+	Config *objects.MaintenanceWindow
+}
+
+type UpdateMaintenanceWindowInArgs struct {
+	OldCfg  *objects.MaintenanceWindow
+	NewCfg  *objects.MaintenanceWindow
+	AttrSet []bool
+}
+
+type DeleteMaintenanceWindowInArgs struct {
+	Config *objects.MaintenanceWindow
+}
+
+type MaintenanceWindowOutArgs struct {
+	RetVal bool
+	Err    error
+}
+
+type GetEventIngestStateInArgs struct {
+	OwnerName string
+}
+
+type GetEventIngestStateOutArgs struct {
+	Obj *objects.EventIngestState
+	Err error
+}
+
+type GetBulkEventIngestStateOutArgs struct {
+	BulkInfo *objects.EventIngestStateGetInfo
+	Err      error
+}
@@ -0,0 +1,97 @@
+//
+//Copyright [2016] [SnapRoute Inc]
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//       Unless required by applicable law or agreed to in writing, software
+//       distributed under the License is distributed on an "AS IS" BASIS,
+//       WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//       See the License for the specific language governing permissions and
+//       limitations under the License.
+//
+// _______  __       __________   ___      _______.____    __    ____  __  .___________.  ______  __    __
+// |   ____||  |     |   ____\  \ /  /     /       |\   \  /  \  /   / |  | |           | /      ||  |  |  |
+// |  |__   |  |     |  |__   \  V  /     |   (----` \   \/    \/   /  |  | `---|  |----`|  ,----'|  |__|  |
+// |   __|  |  |     |   __|   >   <       \   \      \            /   |  |     |  |     |  |     |   __   |
+// |  |     |  `----.|  |____ /  .  \  .----)   |      \    /\    /    |  |     |  |     |  `----.|  |  |  |
+// |__|     |_______||_______/__/ \__\ |_______/        \__/  \__/     |__|     |__|      \______||__|  |__|
+//
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	nanomsg "github.com/op/go-nanomsg"
+	"infra/sysd/sysdCommonDefs"
+	"time"
+)
+
+const (
+	SYSD_RECV_RETRY_MIN = 100 * time.Millisecond
+	SYSD_RECV_RETRY_MAX = 30 * time.Second
+)
+
+// ListenToSysdUpdates hands over the daemon keepalive status published by
+// sysd to fault manager
+func (server *FMGRServer) ListenToSysdUpdates() {
+	sysdSubSocket, err := nanomsg.NewSubSocket()
+	if err != nil {
+		server.Logger.Err(fmt.Sprintln("Failed to open sysd sub socket", err))
+		return
+	}
+	defer sysdSubSocket.Close()
+	_, err = sysdSubSocket.Connect(sysdCommonDefs.PUB_SOCKET_ADDR)
+	if err != nil {
+		server.Logger.Err(fmt.Sprintln("Failed to connect to sysd pub socket", err))
+		return
+	}
+	err = sysdSubSocket.Subscribe("")
+	if err != nil {
+		server.Logger.Err(fmt.Sprintln("Failed to subscribe to sysd notifications", err))
+		return
+	}
+	err = sysdSubSocket.SetRecvBuffer(1024 * 1024)
+	if err != nil {
+		server.Logger.Err(fmt.Sprintln("Failed to set receive buffer size for sysd sub socket", err))
+		return
+	}
+	retryInterval := SYSD_RECV_RETRY_MIN
+	for {
+		rxBuf, err := sysdSubSocket.Recv(0)
+		if err != nil {
+			// Back off so that a broken socket does not spin the listener
+			server.Logger.Err(fmt.Sprintln("Error receiving sysd notification, retrying in", retryInterval, err))
+			time.Sleep(retryInterval)
+			retryInterval *= 2
+			if retryInterval > SYSD_RECV_RETRY_MAX {
+				retryInterval = SYSD_RECV_RETRY_MAX
+			}
+			continue
+		}
+		retryInterval = SYSD_RECV_RETRY_MIN
+		var notification sysdCommonDefs.Notification
+		err = json.Unmarshal(rxBuf, &notification)
+		if err != nil {
+			server.Logger.Err(fmt.Sprintln("Unable to unmarshal sysd notification", err))
+			continue
+		}
+		if notification.Type != sysdCommonDefs.KA_DAEMON {
+			continue
+		}
+		var status sysdCommonDefs.DaemonStatus
+		err = json.Unmarshal(notification.Payload, &status)
+		if err != nil {
+			server.Logger.Err(fmt.Sprintln("Unable to unmarshal daemon status", err))
+			continue
+		}
+		err = server.fMgr.ProcessDaemonStatus(status)
+		if err != nil {
+			server.Logger.Err(fmt.Sprintln("Error processing daemon status of", status.Name, err))
+		}
+	}
+}